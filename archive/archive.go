@@ -0,0 +1,305 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/zfullio/price-placements/v2/freshness"
+	"github.com/zfullio/price-placements/v2/option"
+	"github.com/zfullio/price-placements/v2/platform"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	objectsDir   = "objects"
+	snapshotsDir = "snapshots"
+
+	objectExt   = ".xml.gz"
+	snapshotExt = ".json"
+)
+
+// Snapshot describes a fetched feed body. Bodies are shared by snapshots with the same Hash.
+type Snapshot struct {
+	Feed         string      `json:"feed"`
+	Platform     string      `json:"platform"`
+	URL          string      `json:"url"`
+	Fetched      time.Time   `json:"fetched"`
+	LastModified time.Time   `json:"last_modified"`
+	Header       http.Header `json:"header,omitempty"`
+	Hash         string      `json:"hash"`
+	Size         int64       `json:"size"`
+}
+
+// Archive stores raw feed bodies on disk, gzip-compressed and deduplicated by their sha256 hash.
+//
+// The layout is objects/<hash[:2]>/<hash>.xml.gz for bodies and snapshots/<feed>/<fetched>.json for metadata,
+// where <feed> is the hex-encoded feed name.
+type Archive struct {
+	dir string
+	// mu keeps Prune from collecting a body whose snapshot is being saved.
+	mu sync.Mutex
+}
+
+func New(dir string) (*Archive, error) {
+	for _, name := range []string{objectsDir, snapshotsDir} {
+		if err := os.MkdirAll(filepath.Join(dir, name), 0o755); err != nil {
+			return nil, fmt.Errorf("can't create archive directory. Error:%w", err)
+		}
+	}
+
+	return &Archive{dir: dir}, nil
+}
+
+// Save stores the body unless an identical one is archived already and records the snapshot.
+func (a *Archive) Save(snapshot Snapshot, body []byte) (Snapshot, error) {
+	dir, err := a.feedDir(snapshot.Feed)
+	if err != nil {
+		return snapshot, err
+	}
+
+	if snapshot.Fetched.IsZero() {
+		snapshot.Fetched = time.Now()
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	snapshot.Fetched = snapshot.Fetched.UTC()
+	snapshot.Hash = freshness.Hash(body)
+	snapshot.Size = int64(len(body))
+
+	if err := a.writeObject(snapshot.Hash, body); err != nil {
+		return snapshot, err
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return snapshot, fmt.Errorf("can't encode snapshot. Error:%w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return snapshot, fmt.Errorf("can't create snapshot directory. Error:%w", err)
+	}
+
+	if err := writeFile(filepath.Join(dir, snapshotName(snapshot.Fetched)), data); err != nil {
+		return snapshot, fmt.Errorf("can't save snapshot. Error:%w", err)
+	}
+
+	return snapshot, nil
+}
+
+func (a *Archive) writeObject(hash string, body []byte) error {
+	name := a.objectPath(hash)
+
+	if _, err := os.Stat(name); err == nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return fmt.Errorf("can't create object directory. Error:%w", err)
+	}
+
+	var buffer bytes.Buffer
+
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(body); err != nil {
+		return fmt.Errorf("can't compress feed. Error:%w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("can't compress feed. Error:%w", err)
+	}
+
+	if err := writeFile(name, buffer.Bytes()); err != nil {
+		return fmt.Errorf("can't save feed body. Error:%w", err)
+	}
+
+	return nil
+}
+
+// List returns the snapshots of the feed, oldest first.
+func (a *Archive) List(feed string) ([]Snapshot, error) {
+	dir, err := a.feedDir(feed)
+	if err != nil {
+		return nil, err
+	}
+
+	return listDir(dir)
+}
+
+func listDir(dir string) ([]Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("can't list snapshots. Error:%w", err)
+	}
+
+	snapshots := make([]Snapshot, 0, len(entries))
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), snapshotExt) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return snapshots, fmt.Errorf("can't read snapshot. Error:%w", err)
+		}
+
+		var snapshot Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			return snapshots, fmt.Errorf("can't decode snapshot %s. Error:%w", entry.Name(), err)
+		}
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].Fetched.Before(snapshots[j].Fetched)
+	})
+
+	return snapshots, nil
+}
+
+// At returns the latest snapshot of the feed fetched not after moment, i.e. what was published at that time.
+func (a *Archive) At(feed string, moment time.Time) (Snapshot, error) {
+	snapshots, err := a.List(feed)
+	if err != nil {
+		return Snapshot{}, err
+	}
+
+	for idx := len(snapshots) - 1; idx >= 0; idx-- {
+		if !snapshots[idx].Fetched.After(moment) {
+			return snapshots[idx], nil
+		}
+	}
+
+	return Snapshot{}, fmt.Errorf("feed %s has no snapshot before %s", feed, moment.Format(time.RFC3339))
+}
+
+// Open returns the uncompressed body of the snapshot.
+func (a *Archive) Open(snapshot Snapshot) (io.ReadCloser, error) {
+	file, err := os.Open(a.objectPath(snapshot.Hash))
+	if err != nil {
+		return nil, fmt.Errorf("can't open feed body. Error:%w", err)
+	}
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+
+		return nil, fmt.Errorf("can't decompress feed body. Error:%w", err)
+	}
+
+	return readCloser{Reader: reader, close: func() error {
+		return errors.Join(reader.Close(), file.Close())
+	}}, nil
+}
+
+func (a *Archive) Body(snapshot Snapshot) ([]byte, error) {
+	reader, err := a.Open(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	defer reader.Close()
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("can't decompress feed body. Error:%w", err)
+	}
+
+	if freshness.Hash(body) != snapshot.Hash {
+		return nil, fmt.Errorf("feed body %s is corrupted", snapshot.Hash)
+	}
+
+	return body, nil
+}
+
+// Load creates the platform feed of the snapshot and decodes the archived body as if it was just fetched.
+func (a *Archive) Load(snapshot Snapshot, opts ...option.Option) (platform.Feed, error) {
+	body, err := a.Body(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := platform.New(snapshot.Platform, nil, snapshot.URL, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := feed.Restore(body, snapshot.Header); err != nil {
+		return nil, err
+	}
+
+	return feed, nil
+}
+
+func (a *Archive) objectPath(hash string) string {
+	prefix := hash
+	if len(prefix) > 2 {
+		prefix = prefix[:2]
+	}
+
+	return filepath.Join(a.dir, objectsDir, prefix, hash+objectExt)
+}
+
+type readCloser struct {
+	io.Reader
+	close func() error
+}
+
+func (r readCloser) Close() error {
+	return r.close()
+}
+
+// writeFile writes through a temporary file so readers never see a partial file.
+func writeFile(name string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(name), ".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(file.Name())
+
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+
+		return err
+	}
+
+	return os.Rename(file.Name(), name)
+}
+
+func snapshotName(fetched time.Time) string {
+	return fetched.UTC().Format("20060102T150405.000000000Z") + snapshotExt
+}
+
+// feedDir returns the snapshot directory of the feed. The name is hex-encoded, so distinct
+// names never share a directory and no name can point outside the archive.
+func (a *Archive) feedDir(feed string) (string, error) {
+	if feed == "" || feed == "." || feed == ".." {
+		return "", fmt.Errorf("invalid feed name %q", feed)
+	}
+
+	return filepath.Join(a.dir, snapshotsDir, hex.EncodeToString([]byte(feed))), nil
+}
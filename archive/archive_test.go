@@ -0,0 +1,105 @@
+package archive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSaveDeduplicatesBodies(t *testing.T) {
+	archive, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	bodies := []string{"<feed>1</feed>", "<feed>1</feed>", "<feed>2</feed>"}
+
+	for idx, body := range bodies {
+		if _, err := archive.Save(Snapshot{Feed: "avito", Fetched: start.Add(time.Duration(idx) * time.Hour)}, []byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshots, err := archive.List("avito")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(snapshots) != len(bodies) {
+		t.Fatalf("got %v snapshots, want %v", len(snapshots), len(bodies))
+	}
+
+	objects, err := filepath.Glob(filepath.Join(archive.dir, objectsDir, "*", "*"+objectExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(objects) != 2 {
+		t.Fatalf("got %v bodies, want 2", len(objects))
+	}
+
+	snapshot, err := archive.At("avito", start.Add(90*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, err := archive.Body(snapshot)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(body) != bodies[1] {
+		t.Fatalf("At returned %q, want %q", body, bodies[1])
+	}
+
+	if _, err := archive.At("avito", start.Add(-time.Second)); err == nil {
+		t.Fatal("At before the first snapshot has no error")
+	}
+}
+
+func TestFeedNames(t *testing.T) {
+	archive, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{"a/b", "a_b", "a b", "A_B"}
+
+	for _, name := range names {
+		if _, err := archive.Save(Snapshot{Feed: name}, []byte(name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, name := range names {
+		snapshots, err := archive.List(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(snapshots) != 1 || snapshots[0].Feed != name {
+			t.Fatalf("feed %q has snapshots %v", name, snapshots)
+		}
+	}
+
+	for _, name := range []string{"", ".", ".."} {
+		if _, err := archive.Save(Snapshot{Feed: name}, []byte(name)); err == nil {
+			t.Errorf("Save of feed %q has no error", name)
+		}
+
+		if _, err := archive.List(name); err == nil {
+			t.Errorf("List of feed %q has no error", name)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Join(archive.dir, snapshotsDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != len(names) {
+		t.Fatalf("got %v feed directories, want %v", len(entries), len(names))
+	}
+}
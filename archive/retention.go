@@ -0,0 +1,126 @@
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Retention selects the snapshots kept by Prune. Zero fields are not applied and the latest
+// snapshot of a feed is always kept.
+type Retention struct {
+	// MaxAge removes snapshots fetched earlier.
+	MaxAge time.Duration
+	// MaxCount keeps only the latest snapshots of a feed.
+	MaxCount int
+	// KeepDaily keeps the last snapshot of each of the latest days beyond MaxCount.
+	KeepDaily int
+}
+
+func (r Retention) IsZero() bool {
+	return r.MaxAge <= 0 && r.MaxCount <= 0
+}
+
+// keep reports which of the snapshots, sorted oldest first, are retained.
+func (r Retention) keep(snapshots []Snapshot, now time.Time) []bool {
+	kept := make([]bool, len(snapshots))
+	days := make(map[string]bool)
+
+	for idx := len(snapshots) - 1; idx >= 0; idx-- {
+		snapshot := snapshots[idx]
+		newer := len(snapshots) - 1 - idx
+		day := snapshot.Fetched.UTC().Format(time.DateOnly)
+
+		switch {
+		case newer == 0:
+			kept[idx] = true
+		case r.MaxAge > 0 && now.Sub(snapshot.Fetched) > r.MaxAge:
+			kept[idx] = false
+		case r.MaxCount <= 0 || newer < r.MaxCount:
+			kept[idx] = true
+		case r.KeepDaily > 0 && !days[day] && len(days) < r.KeepDaily:
+			kept[idx] = true
+		}
+
+		if !days[day] && len(days) < r.KeepDaily {
+			days[day] = true
+		}
+	}
+
+	return kept
+}
+
+// Prune removes the snapshots outside the retention and the bodies no snapshot refers to anymore.
+func (a *Archive) Prune(retention Retention, now time.Time) (int, error) {
+	if retention.IsZero() {
+		return 0, nil
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	feeds, err := os.ReadDir(filepath.Join(a.dir, snapshotsDir))
+	if err != nil {
+		return 0, fmt.Errorf("can't list archived feeds. Error:%w", err)
+	}
+
+	removed := 0
+	used := make(map[string]bool)
+
+	for _, feed := range feeds {
+		if !feed.IsDir() {
+			continue
+		}
+
+		snapshots, err := listDir(filepath.Join(a.dir, snapshotsDir, feed.Name()))
+		if err != nil {
+			return removed, err
+		}
+
+		for idx, keep := range retention.keep(snapshots, now) {
+			snapshot := snapshots[idx]
+
+			if keep {
+				used[snapshot.Hash] = true
+
+				continue
+			}
+
+			name := filepath.Join(a.dir, snapshotsDir, feed.Name(), snapshotName(snapshot.Fetched))
+			if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return removed, fmt.Errorf("can't remove snapshot. Error:%w", err)
+			}
+
+			removed++
+		}
+	}
+
+	return removed, a.collect(used)
+}
+
+// collect removes the bodies that are not used.
+func (a *Archive) collect(used map[string]bool) error {
+	return filepath.WalkDir(filepath.Join(a.dir, objectsDir), func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), objectExt) {
+			return nil
+		}
+
+		if used[strings.TrimSuffix(entry.Name(), objectExt)] {
+			return nil
+		}
+
+		if err := os.Remove(path); err != nil {
+			return fmt.Errorf("can't remove feed body. Error:%w", err)
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,86 @@
+package archive
+
+import (
+	"fmt"
+	"github.com/zfullio/price-placements/v2/freshness"
+	"testing"
+	"time"
+)
+
+func TestRetentionKeep(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	// Two snapshots a day for the last five days, oldest first.
+	snapshots := make([]Snapshot, 0, 10)
+	for day := 4; day >= 0; day-- {
+		for _, hour := range []int{6, 18} {
+			snapshots = append(snapshots, Snapshot{Fetched: time.Date(2024, 3, 10-day, hour, 0, 0, 0, time.UTC)})
+		}
+	}
+
+	tests := []struct {
+		name      string
+		retention Retention
+		want      string
+	}{
+		{name: "zero keeps everything", retention: Retention{}, want: "1111111111"},
+		{name: "max count", retention: Retention{MaxCount: 3}, want: "0000000111"},
+		{name: "max age", retention: Retention{MaxAge: 48 * time.Hour}, want: "0000011111"},
+		{name: "max age keeps the latest", retention: Retention{MaxAge: time.Minute}, want: "0000000001"},
+		{name: "keep daily", retention: Retention{MaxCount: 2, KeepDaily: 4}, want: "0001010111"},
+		{name: "keep daily within max age", retention: Retention{MaxCount: 2, KeepDaily: 4, MaxAge: 60 * time.Hour}, want: "0000010111"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ""
+			for _, keep := range tt.retention.keep(snapshots, now) {
+				got += map[bool]string{true: "1", false: "0"}[keep]
+			}
+
+			if got != tt.want {
+				t.Errorf("keep = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrune(t *testing.T) {
+	archive, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	for idx := 0; idx < 5; idx++ {
+		snapshot := Snapshot{Feed: "cian", Fetched: now.Add(time.Duration(idx-5) * time.Hour)}
+		if _, err := archive.Save(snapshot, []byte(fmt.Sprint(idx))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := archive.Prune(Retention{MaxCount: 2}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if removed != 3 {
+		t.Fatalf("removed %v snapshots, want 3", removed)
+	}
+
+	snapshots, err := archive.List("cian")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, snapshot := range snapshots {
+		if _, err := archive.Body(snapshot); err != nil {
+			t.Fatalf("kept snapshot has no body: %v", err)
+		}
+	}
+
+	if _, err := archive.Body(Snapshot{Hash: freshness.Hash([]byte("0"))}); err == nil {
+		t.Fatal("body of a pruned snapshot is kept")
+	}
+}
@@ -1,23 +1,60 @@
 package avito
 
 import (
+	"bytes"
 	"context"
 	"encoding/xml"
-	"errors"
 	"fmt"
+	"github.com/zfullio/price-placements/v2/decoding"
+	"github.com/zfullio/price-placements/v2/freshness"
+	"github.com/zfullio/price-placements/v2/option"
+	"github.com/zfullio/price-placements/v2/schema"
+	"github.com/zfullio/price-placements/v2/summary"
 	"github.com/zfullio/price-placements/v2/transport"
 	"github.com/zfullio/price-placements/v2/validation"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"os"
 	"time"
 )
 
+const (
+	platformName = "avito"
+
+	lotElement = "Ad"
+
+	categoryFlats      = "Квартиры"
+	categoryRooms      = "Комнаты"
+	categoryHouses     = "Дома, дачи, коттеджи"
+	categoryCommercial = "Коммерческая недвижимость"
+	categoryLands      = "Земельные участки"
+	categoryGarages    = "Гаражи и машиноместа"
+
+	operationSell = "Продам"
+	operationRent = "Сдам"
+
+	marketSecondary = "Вторичка"
+	marketNew       = "Новостройка"
+
+	leaseLongTerm = "На длительный срок"
+	leaseDaily    = "Посуточно"
+)
+
 type Feed struct {
-	client       *http.Client
+	transport    *transport.Transport
+	logger       *slog.Logger
 	url          string
 	isGet        bool
+	raw          []byte
+	decoded      decoding.Result
+	rules        validation.Rules
+	header       http.Header
+	DecodeMode   decoding.Mode
 	LastModified time.Time
+	Freshness    freshness.Freshness
+	MaxAge       time.Duration
 	Data         Data
 }
 
@@ -28,160 +65,508 @@ type Data struct {
 	Ad            []Ad     `xml:"Ad"`
 }
 
-func NewFeed(client *http.Client, url string) *Feed {
+func NewFeed(client *http.Client, url string, opts ...option.Option) *Feed {
+	options := option.Apply(opts...)
+
 	return &Feed{
-		client: client,
-		url:    url,
+		transport:  transport.New(client, options.Transport...),
+		logger:     options.Logger.With(slog.String("platform", platformName), slog.String("url", url)),
+		url:        url,
+		rules:      options.Rules,
+		DecodeMode: options.DecodeMode,
+		MaxAge:     options.MaxAge,
 	}
 }
 
 type Ad struct {
-	ID              string  `xml:"Id"`
-	AdStatus        string  `xml:"AdStatus"`
-	AllowEmail      string  `xml:"AllowEmail"`
-	ContactPhone    string  `xml:"ContactPhone"`
-	Latitude        string  `xml:"Latitude"`
-	Longitude       string  `xml:"Longitude"`
-	Description     string  `xml:"Description"`
-	Category        string  `xml:"Category"`
-	OperationType   string  `xml:"OperationType"`
-	Price           int64   `xml:"Price"`
-	Rooms           string  `xml:"Rooms"`
-	Square          float32 `xml:"Square"`
-	BalconyOrLoggia string  `xml:"BalconyOrLoggia"`
-	KitchenSpace    float32 `xml:"KitchenSpace"`
-	ViewFromWindows string  `xml:"ViewFromWindows"`
-	CeilingHeight   string  `xml:"CeilingHeight"`
-	LivingSpace     float32 `xml:"LivingSpace"`
-	Decoration      string  `xml:"Decoration"`
-	DealType        string  `xml:"DealType"`
+	ID              string           `xml:"Id"`
+	AvitoID         string           `xml:"AvitoId"`
+	DateBegin       string           `xml:"DateBegin"`
+	DateEnd         string           `xml:"DateEnd"`
+	ListingFee      string           `xml:"ListingFee"`
+	AdStatus        string           `xml:"AdStatus"`
+	AllowEmail      string           `xml:"AllowEmail"`
+	ManagerName     string           `xml:"ManagerName"`
+	ContactPhone    string           `xml:"ContactPhone"`
+	ContactMethod   string           `xml:"ContactMethod"`
+	Address         string           `xml:"Address"`
+	Latitude        string           `xml:"Latitude"`
+	Longitude       string           `xml:"Longitude"`
+	Description     string           `xml:"Description"`
+	Category        string           `xml:"Category"`
+	OperationType   string           `xml:"OperationType"`
+	AdType          string           `xml:"AdType"`
+	Price           validation.Int   `xml:"Price"`
+	VideoURL        string           `xml:"VideoURL"`
+	VideoFileURL    string           `xml:"VideoFileURL"`
+	Rooms           string           `xml:"Rooms"`
+	Square          validation.Float `xml:"Square"`
+	BalconyOrLoggia string           `xml:"BalconyOrLoggia"`
+	KitchenSpace    validation.Float `xml:"KitchenSpace"`
+	ViewFromWindows string           `xml:"ViewFromWindows"`
+	CeilingHeight   string           `xml:"CeilingHeight"`
+	LivingSpace     validation.Float `xml:"LivingSpace"`
+	Decoration      string           `xml:"Decoration"`
+	DealType        string           `xml:"DealType"`
 	RoomType        struct {
 		Option string `xml:"Option"`
 	} `xml:"RoomType"`
-	Status           string `xml:"Status"`
-	Floor            int64  `xml:"Floor"`
-	Floors           int64  `xml:"Floors"`
-	HouseType        string `xml:"HouseType"`
-	MarketType       string `xml:"MarketType"`
-	PropertyRights   string `xml:"PropertyRights"`
-	NewDevelopmentID string `xml:"NewDevelopmentId"`
-	Images           struct {
+	Status               string           `xml:"Status"`
+	Floor                int64            `xml:"Floor"`
+	Floors               int64            `xml:"Floors"`
+	HouseType            string           `xml:"HouseType"`
+	MarketType           string           `xml:"MarketType"`
+	PropertyRights       string           `xml:"PropertyRights"`
+	NewDevelopmentID     string           `xml:"NewDevelopmentId"`
+	BuiltYear            int64            `xml:"BuiltYear"`
+	BalconyOrLoggiaMulti Options          `xml:"BalconyOrLoggiaMulti"`
+	BathroomMulti        Options          `xml:"BathroomMulti"`
+	Renovation           string           `xml:"Renovation"`
+	PassengerElevator    string           `xml:"PassengerElevator"`
+	FreightElevator      string           `xml:"FreightElevator"`
+	Parking              Options          `xml:"Parking"`
+	Courtyard            Options          `xml:"Courtyard"`
+	SaleOptions          Options          `xml:"SaleOptions"`
+	SSAdditionally       Options          `xml:"SSAdditionally"`
+	NDAdditionally       Options          `xml:"NDAdditionally"`
+	Furniture            Options          `xml:"Furniture"`
+	LeaseType            string           `xml:"LeaseType"`
+	LeaseDeposit         string           `xml:"LeaseDeposit"`
+	LeaseCommissionSize  string           `xml:"LeaseCommissionSize"`
+	LeaseBeds            int64            `xml:"LeaseBeds"`
+	LeaseSleepingPlaces  int64            `xml:"LeaseSleepingPlaces"`
+	LeaseComfort         Options          `xml:"LeaseComfort"`
+	LeaseAppliances      Options          `xml:"LeaseAppliances"`
+	LeaseMultimedia      Options          `xml:"LeaseMultimedia"`
+	LeaseAdditionally    Options          `xml:"LeaseAdditionally"`
+	ObjectType           string           `xml:"ObjectType"`
+	LandArea             validation.Float `xml:"LandArea"`
+	LandStatus           string           `xml:"LandStatus"`
+	WallsType            string           `xml:"WallsType"`
+	DistanceToCity       int64            `xml:"DistanceToCity"`
+	HouseServices        Options          `xml:"HouseServices"`
+	BuildingType         string           `xml:"BuildingType"`
+	BuildingClass        string           `xml:"BuildingClass"`
+	Entrance             string           `xml:"Entrance"`
+	ParkingType          string           `xml:"ParkingType"`
+	RentalType           string           `xml:"RentalType"`
+	Images               struct {
 		Image []struct {
 			URL string `xml:"url,attr"`
 		} `xml:"Image"`
 	} `xml:"Images"`
 }
 
+type Options struct {
+	Option []string `xml:"Option"`
+}
+
 func (f *Feed) Get(ctx context.Context) error {
 	err := f.GetInfo(ctx)
 	if err != nil {
 		return fmt.Errorf("can't get feed info. Error:%w", err)
 	}
 
-	resp, err := transport.GetResponse(ctx, f.client, f.url)
+	resp, err := f.transport.Get(ctx, f.url)
 	if err != nil {
 		return fmt.Errorf("can't get feed data. Error:%w", err)
 	}
 
 	defer resp.Body.Close()
 
-	responseBody, err := io.ReadAll(resp.Body)
+	responseBody, err := f.transport.ReadBody(resp)
+	if err != nil {
+		return err
+	}
+
+	if f.Freshness.Header.IsZero() {
+		f.Freshness.Header, err = freshness.ParseHeader(resp.Header)
+		if err != nil {
+			return err
+		}
+	}
+
+	f.header = resp.Header
+
+	return f.load(responseBody)
+}
+
+func (f *Feed) ReadFile(name string) error {
+	body, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("can't read feed file. Error:%w", err)
+	}
+
+	f.Freshness.FileModTime, err = freshness.FileModTime(name)
+	if err != nil {
+		return err
+	}
+
+	return f.load(body)
+}
+
+// Restore decodes body as if it was fetched with the response header, e.g. from an archived snapshot.
+func (f *Feed) Restore(body []byte, header http.Header) error {
+	var err error
+
+	f.Freshness.Header, err = freshness.ParseHeader(header)
 	if err != nil {
 		return err
 	}
 
-	err = xml.Unmarshal(responseBody, &f.Data)
+	f.header = header
+
+	return f.load(body)
+}
+
+func (f *Feed) Read(r io.Reader) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("can't read feed. Error:%w", err)
+	}
+
+	return f.load(body)
+}
+
+func (f *Feed) load(body []byte) error {
+	var err error
+
+	f.Data = Data{}
+	f.raw = body
+
+	f.decoded, err = decoding.Decode(body, &f.Data, f.DecodeMode, lotElement)
 	if err != nil {
 		return err
 	}
 
 	f.isGet = true
+	f.LastModified, _ = f.Freshness.Resolve()
 
-	//TODO Исправить значение f.LastModified
+	f.logger.Debug("feed decoded", slog.Int("bytes", len(body)), slog.Int("lots", f.Lots()),
+		slog.Int("decode_findings", len(f.decoded.Findings)), slog.Time("last_modified", f.LastModified))
 
 	return nil
 }
 
+func (f *Feed) IsStale(now time.Time) bool {
+	return f.Freshness.IsStale(now, f.MaxAge)
+}
+
+func (f *Feed) CheckFreshness(now time.Time) []validation.Finding {
+	return f.rules.Filter(f.Freshness.Check(now, f.MaxAge))
+}
+
+func (f *Feed) Raw() []byte {
+	return f.raw
+}
+
+// Header returns the response header of the last download.
+func (f *Feed) Header() http.Header {
+	return f.header
+}
+
+func (f *Feed) ContentHash() string {
+	return freshness.Hash(f.raw)
+}
+
+func (f *Feed) Lots() int {
+	return len(f.Data.Ad)
+}
+
+// lotIDs returns the lot IDs in document order.
+func (f *Feed) lotIDs() []string {
+	ids := make([]string, 0, len(f.Data.Ad))
+	for _, lot := range f.Data.Ad {
+		ids = append(ids, lot.ID)
+	}
+
+	return ids
+}
+
+func (f *Feed) Summaries() []summary.Lot {
+	lots := make([]summary.Lot, 0, len(f.Data.Ad))
+
+	for idx, lot := range f.Data.Ad {
+		images := make([]string, 0, len(lot.Images.Image))
+		for _, image := range lot.Images.Image {
+			images = append(images, image.URL)
+		}
+
+		lots = append(lots, summary.Lot{
+			ID:       lot.ID,
+			Title:    summary.Title(lot.Category, lot.Rooms, lot.Address),
+			Images:   images,
+			Position: f.decoded.Positions.At(lotElement, idx),
+		})
+	}
+
+	return lots
+}
+
+func (f *Feed) Stats() summary.Stats {
+	offers := make([]summary.Offer, 0, len(f.Data.Ad))
+
+	for _, lot := range f.Data.Ad {
+		decoration := lot.Decoration
+		if decoration == "" {
+			decoration = lot.Renovation
+		}
+
+		offers = append(offers, summary.Offer{
+			Rooms:      offerRooms(lot.Rooms),
+			Building:   lot.NewDevelopmentID,
+			Decoration: decoration,
+			Price:      float64(lot.Price.Value),
+			Area:       lot.Square.Value,
+			Photos:     len(lot.Images.Image),
+		})
+	}
+
+	return summary.Compute(offers)
+}
+
+func offerRooms(value string) string {
+	rooms := validation.ParseRooms(value)
+
+	switch rooms.Kind {
+	case validation.RoomsStudio:
+		return summary.RoomsStudio
+	case validation.RoomsFreeLayout:
+		return summary.RoomsFreeLayout
+	case validation.RoomsCount:
+		return summary.Rooms(rooms.Count)
+	default:
+		return value
+	}
+}
+
 func (f *Feed) GetInfo(ctx context.Context) error {
-	resp, err := transport.GetOnlyHeader(ctx, f.client, f.url)
+	resp, err := f.transport.Head(ctx, f.url)
 	if err != nil {
 		return err
 	}
 
 	defer resp.Body.Close()
 
-	attributeLastModified := resp.Header.Get("Last-Modified")
-	if attributeLastModified != "" {
-		lastModifiedDate, err := time.Parse(time.RFC1123, resp.Header.Get("Last-Modified"))
-		if err != nil {
-			return err
-		}
+	lastModified, err := freshness.ParseHeader(resp.Header)
+	if err != nil {
+		return err
+	}
 
-		f.LastModified = lastModifiedDate
-	} else {
-		log.Println("Header not contains `Last-Modified`")
+	if lastModified.IsZero() {
+		f.logger.DebugContext(ctx, "header not contains `Last-Modified`")
 	}
 
+	f.Freshness.Header = lastModified
+	f.LastModified, _ = f.Freshness.Resolve()
+
 	return nil
 }
 
 func (f *Feed) Check() ([]string, error) {
+	findings, err := f.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return validation.Strings(findings), nil
+}
+
+func (f *Feed) Validate() ([]validation.Finding, error) {
 	if !f.isGet {
-		return nil, errors.New("feed not got")
+		return nil, &transport.NotFetchedError{URL: f.url}
 	}
 
-	results := make([]string, 0)
+	results := make([]validation.Finding, 0, len(f.decoded.Findings))
+	results = append(results, f.decoded.LotFindings(lotElement, f.lotIDs())...)
 
 	if len(f.Data.Ad) < 2 {
-		results = append(results, validation.MsgEmptyFeed)
+		validation.Add(&results, validation.RuleFeedSize, "", "Ads", validation.MsgEmptyFeed)
 
-		return results, nil
+		return f.rules.Filter(results), nil
 	}
 
 	if len(f.Data.Ad) <= 10 {
-		results = append(results, fmt.Sprintf("feed contains only %v items", len(f.Data.Ad)))
+		validation.Add(&results, validation.RuleFeedSize, "", "Ads", fmt.Sprintf("feed contains only %v items", len(f.Data.Ad)))
 
-		return results, nil
+		return f.rules.Filter(results), nil
 	}
 
 	for idx, lot := range f.Data.Ad {
-		validation.CheckStringWithPos(idx, "Ad", "ID", lot.ID, &results)
+		start := len(results)
+		validation.CheckFilledWithPos(idx, "Ad", "ID", lot.ID, &results)
 		id := lot.ID
-		validation.CheckStringWithID(id, "Ad", "ContactPhone", lot.ContactPhone, &results)
-		validation.CheckStringWithID(id, "Ad", "Description", lot.Description, &results)
-		validation.CheckStringWithID(id, "Ad", "Category", lot.Category, &results)
-		validation.CheckZeroWithID(id, "Ad", "Price", int(lot.Price), &results)
-		validation.CheckStringWithID(id, "Ad", "OperationType", lot.OperationType, &results)
-		validation.CheckStringWithID(id, "Ad", "MarketType", lot.MarketType, &results)
-		validation.CheckStringWithID(id, "Ad", "HouseType", lot.HouseType, &results)
-		validation.CheckZeroWithID(id, "Ad", "Floor", int(lot.Floor), &results)
-		validation.CheckZeroWithID(id, "Ad", "Floors", int(lot.Floors), &results)
-		validation.CheckStringWithID(id, "Ad", "Rooms", lot.Rooms, &results)
-		validation.CheckZeroWithID(id, "Ad", "Square", lot.Square, &results)
-
-		if lot.LivingSpace == 0 && lot.Rooms != "Студия" {
-			results = append(results, fmt.Sprintf("field LivingSpace is empty. InternalID: %v", lot.ID))
+		validation.CheckFilledWithID(id, "Ad", "ContactPhone", lot.ContactPhone, &results)
+		validation.CheckFilledWithID(id, "Ad", "Description", lot.Description, &results)
+		validation.CheckFilledWithID(id, "Ad", "Category", lot.Category, &results)
+		validation.CheckNonZeroWithID(id, "Ad", "Price", lot.Price, &results)
+		validation.CheckFilledWithID(id, "Ad", "OperationType", lot.OperationType, &results)
+
+		checkCommon(lot, &results)
+
+		// Ads without a known category get the flat checks, which every ad got before categories were told apart.
+		switch lot.Category {
+		case categoryHouses:
+			checkHouse(lot, &results)
+		case categoryCommercial:
+			checkCommercial(lot, &results)
+		case categoryRooms, categoryLands, categoryGarages:
+		default:
+			checkFlat(lot, &results)
 		}
 
-		validation.CheckStringWithID(id, "Ad", "Status", lot.Status, &results)
-		validation.CheckStringWithID(id, "Ad", "NewDevelopmentId", lot.NewDevelopmentID, &results)
-		validation.CheckStringWithID(id, "Ad", "PropertyRights", lot.PropertyRights, &results)
-		validation.CheckStringWithID(id, "Ad", "Decoration", lot.Decoration, &results)
+		for idx, image := range lot.Images.Image {
+			validation.CheckFilledWithPos(idx, "Images.Image", "URL", image.URL, &results)
+		}
 
-		if lot.Floor > lot.Floors {
-			results = append(results, fmt.Sprintf("field Floor is bigger than Floors. InternalID: %v", lot.ID))
+		if len(lot.Images.Image) < 3 || len(lot.Images.Image) > 40 {
+			validation.Add(&results, validation.RuleImages, lot.ID, "Ad.Images.Image", fmt.Sprintf("field Images.Image contains '%v' items", len(lot.Images.Image)))
 		}
 
-		for idx, image := range lot.Images.Image {
-			validation.CheckStringWithPos(idx, "Images.Image", "URL", image.URL, &results)
+		validation.SetPosition(results[start:], f.decoded.Positions.At(lotElement, idx))
+	}
+
+	return f.rules.Filter(results), nil
+}
+
+func checkCommon(lot Ad, results *[]validation.Finding) {
+	id := lot.ID
+
+	validation.CheckOneOfWithID(id, "Ad", "Category", lot.Category, []string{
+		categoryFlats, categoryRooms, categoryHouses, categoryCommercial, categoryLands, categoryGarages,
+	}, results)
+	validation.CheckOneOfWithID(id, "Ad", "OperationType", lot.OperationType, []string{operationSell, operationRent}, results)
+	validation.CheckOneOfWithID(id, "Ad", "ListingFee", lot.ListingFee, []string{"Package", "PackageSingle", "Single"}, results)
+	validation.CheckOneOfWithID(id, "Ad", "AdStatus", lot.AdStatus, []string{
+		"Free", "Highlight", "XL", "x2_1", "x2_7", "x5_1", "x5_7", "x10_1", "x10_7", "x15_1", "x15_7", "x20_1", "x20_7",
+	}, results)
+	validation.CheckOneOfWithID(id, "Ad", "ContactMethod", lot.ContactMethod, []string{
+		"По телефону и в сообщениях", "По телефону", "В сообщениях",
+	}, results)
+
+	if lot.Address == "" && (lot.Latitude == "" || lot.Longitude == "") {
+		validation.Add(results, validation.RuleEmpty, id, "Ad.Address", "fields Address and Latitude/Longitude are empty")
+	}
+
+	dateBegin, okBegin := parseDate(id, "DateBegin", lot.DateBegin, results)
+	dateEnd, okEnd := parseDate(id, "DateEnd", lot.DateEnd, results)
+
+	if okBegin && okEnd && dateEnd.Before(dateBegin) {
+		validation.Add(results, validation.RuleConsistency, id, "Ad.DateEnd", "field DateEnd is before DateBegin")
+	}
+
+	checkURL(id, "VideoURL", lot.VideoURL, results)
+	checkURL(id, "VideoFileURL", lot.VideoFileURL, results)
+}
+
+func checkFlat(lot Ad, results *[]validation.Finding) {
+	id := lot.ID
+
+	validation.CheckFilledWithID(id, "Ad", "MarketType", lot.MarketType, results)
+	validation.CheckOneOfWithID(id, "Ad", "MarketType", lot.MarketType, []string{marketSecondary, marketNew}, results)
+	validation.CheckFilledWithID(id, "Ad", "HouseType", lot.HouseType, results)
+	validation.CheckNumberWithID(id, "Ad", "Floor", int(lot.Floor), results)
+	validation.CheckNumberWithID(id, "Ad", "Floors", int(lot.Floors), results)
+	validation.CheckFilledWithID(id, "Ad", "Rooms", lot.Rooms, results)
+	validation.CheckNonZeroWithID(id, "Ad", "Square", lot.Square, results)
+
+	layout := validation.Layout{
+		Field:    "Rooms",
+		Rooms:    validation.ParseRooms(lot.Rooms),
+		RoomType: lot.RoomType.Option,
+		Total:    lot.Square.Value,
+	}
+
+	validation.CheckLivingArea(id, "Ad", "LivingSpace", layout, lot.LivingSpace.Value, results)
+	validation.CheckAreaSum(id, "Ad", lot.Square.Value, lot.LivingSpace.Value, lot.KitchenSpace.Value, results)
+	validation.CheckRooms(id, "Ad", layout, results)
+
+	validation.CheckFilledWithID(id, "Ad", "PropertyRights", lot.PropertyRights, results)
+
+	validation.CheckFilledWithID(id, "Ad", "Status", lot.Status, results)
+	validation.CheckFilledWithID(id, "Ad", "NewDevelopmentId", lot.NewDevelopmentID, results)
+	validation.CheckFilledWithID(id, "Ad", "Decoration", lot.Decoration, results)
+
+	validation.CheckOneOfWithID(id, "Ad", "Renovation", lot.Renovation, []string{
+		"Требуется", "Косметический", "Евро", "Дизайнерский",
+	}, results)
+	validation.CheckOneOfWithID(id, "Ad", "PassengerElevator", lot.PassengerElevator, []string{"нет", "1", "2", "3", "4"}, results)
+	validation.CheckOneOfWithID(id, "Ad", "FreightElevator", lot.FreightElevator, []string{"нет", "1", "2", "3", "4"}, results)
+
+	if lot.Floor > lot.Floors {
+		validation.Add(results, validation.RuleConsistency, lot.ID, "Ad.Floor", "field Floor is bigger than Floors")
+	}
+
+	if lot.OperationType == operationRent {
+		validation.CheckFilledWithID(id, "Ad", "LeaseType", lot.LeaseType, results)
+		validation.CheckOneOfWithID(id, "Ad", "LeaseType", lot.LeaseType, []string{leaseLongTerm, leaseDaily}, results)
+
+		if lot.LeaseType == leaseLongTerm {
+			validation.CheckFilledWithID(id, "Ad", "LeaseDeposit", lot.LeaseDeposit, results)
 		}
+	}
+}
 
-		if len(lot.Images.Image) < 3 || len(lot.Images.Image) > 40 {
-			results = append(results, fmt.Sprintf("field Images.Image contains '%v' items. InternalID: %v", len(lot.Images.Image), lot.ID))
+func checkHouse(lot Ad, results *[]validation.Finding) {
+	id := lot.ID
+
+	validation.CheckFilledWithID(id, "Ad", "ObjectType", lot.ObjectType, results)
+	validation.CheckOneOfWithID(id, "Ad", "ObjectType", lot.ObjectType, []string{"Дом", "Дача", "Коттедж", "Таунхаус"}, results)
+	validation.CheckNonZeroWithID(id, "Ad", "Square", lot.Square, results)
+	validation.CheckNonZeroWithID(id, "Ad", "LandArea", lot.LandArea, results)
+	validation.CheckNumberWithID(id, "Ad", "Floors", int(lot.Floors), results)
+	validation.CheckFilledWithID(id, "Ad", "WallsType", lot.WallsType, results)
+
+	if lot.OperationType == operationRent {
+		validation.CheckFilledWithID(id, "Ad", "LeaseType", lot.LeaseType, results)
+		validation.CheckOneOfWithID(id, "Ad", "LeaseType", lot.LeaseType, []string{leaseLongTerm, leaseDaily}, results)
+	}
+}
+
+func checkCommercial(lot Ad, results *[]validation.Finding) {
+	id := lot.ID
+
+	validation.CheckFilledWithID(id, "Ad", "ObjectType", lot.ObjectType, results)
+	validation.CheckNonZeroWithID(id, "Ad", "Square", lot.Square, results)
+	validation.CheckFilledWithID(id, "Ad", "PropertyRights", lot.PropertyRights, results)
+	validation.CheckFilledWithID(id, "Ad", "BuildingType", lot.BuildingType, results)
+	validation.CheckOneOfWithID(id, "Ad", "BuildingClass", lot.BuildingClass, []string{"A", "B", "C", "D"}, results)
+
+	if lot.Floor > lot.Floors && lot.Floors != 0 {
+		validation.Add(results, validation.RuleConsistency, lot.ID, "Ad.Floor", "field Floor is bigger than Floors")
+	}
+
+	if lot.OperationType == operationRent {
+		validation.CheckFilledWithID(id, "Ad", "RentalType", lot.RentalType, results)
+	}
+}
+
+func checkURL(ID string, fieldName string, value string, results *[]validation.Finding) {
+	if value == "" {
+		return
+	}
+
+	parsedURL, err := url.ParseRequestURI(value)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") {
+		validation.Add(results, validation.RuleFormat, ID, "Ad."+fieldName, fmt.Sprintf("field %s is not a valid URL", fieldName))
+	}
+}
+
+func parseDate(ID string, fieldName string, value string, results *[]validation.Finding) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range []string{time.DateOnly, time.RFC3339} {
+		date, err := time.Parse(layout, value)
+		if err == nil {
+			return date, true
 		}
 	}
 
-	return results, nil
+	validation.Add(results, validation.RuleFormat, ID, "Ad."+fieldName, fmt.Sprintf("field %s has invalid date '%s'", fieldName, value))
+
+	return time.Time{}, false
 }
 
 type Developments struct {
@@ -215,14 +600,14 @@ type House struct {
 func (f *Feed) GetDevelopments(ctx context.Context) (Developments, error) {
 	url := "https://autoload.avito.ru/format/New_developments.xml"
 
-	resp, err := transport.GetResponse(ctx, f.client, url)
+	resp, err := f.transport.Get(ctx, url)
 	if err != nil {
 		return Developments{}, err
 	}
 
 	defer resp.Body.Close()
 
-	responseBody, err := io.ReadAll(resp.Body)
+	responseBody, err := f.transport.ReadBody(resp)
 	if err != nil {
 		return Developments{}, err
 	}
@@ -235,3 +620,16 @@ func (f *Feed) GetDevelopments(ctx context.Context) (Developments, error) {
 
 	return developments, err
 }
+
+func (f *Feed) CheckStructure() ([]validation.Finding, error) {
+	if !f.isGet {
+		return nil, &transport.NotFetchedError{URL: f.url}
+	}
+
+	findings, err := schema.Validate(bytes.NewReader(f.raw), Schema())
+	if err != nil {
+		return nil, err
+	}
+
+	return f.rules.Filter(findings), nil
+}
@@ -0,0 +1,114 @@
+package avito
+
+import (
+	"github.com/zfullio/price-placements/v2/validation"
+	"strings"
+	"testing"
+)
+
+// validate checks a feed of eleven copies of the ad, the smallest feed Validate checks lot by lot.
+func validate(t *testing.T, lot Ad) map[string]bool {
+	t.Helper()
+
+	lot.ID = "1"
+
+	ads := make([]Ad, 11)
+	for idx := range ads {
+		ads[idx] = lot
+	}
+
+	feed := &Feed{isGet: true, Data: Data{Ad: ads}}
+
+	findings, err := feed.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := make(map[string]bool, len(findings))
+	for _, finding := range findings {
+		paths[finding.Path] = true
+	}
+
+	return paths
+}
+
+func TestValidateFlatRequirements(t *testing.T) {
+	flatPaths := []string{
+		"Ad.MarketType", "Ad.HouseType", "Ad.Floor", "Ad.Floors", "Ad.Rooms", "Ad.Square", "Ad.PropertyRights",
+		"Ad.Status", "Ad.NewDevelopmentId", "Ad.Decoration",
+	}
+
+	tests := []struct {
+		name    string
+		lot     Ad
+		want    []string
+		wantNot []string
+	}{
+		{name: "flat", lot: Ad{Category: categoryFlats}, want: flatPaths},
+		{name: "secondary flat", lot: Ad{Category: categoryFlats, MarketType: marketSecondary}, want: []string{"Ad.Status", "Ad.NewDevelopmentId", "Ad.Decoration"}},
+		{name: "no category", lot: Ad{}, want: append([]string{"Ad.Category"}, flatPaths...)},
+		{name: "misspelled category", lot: Ad{Category: "Квартира"}, want: append([]string{"Ad.Category"}, flatPaths...)},
+		{name: "house", lot: Ad{Category: categoryHouses}, want: []string{"Ad.ObjectType", "Ad.LandArea", "Ad.WallsType"}, wantNot: []string{"Ad.NewDevelopmentId", "Ad.Rooms"}},
+		{name: "commercial", lot: Ad{Category: categoryCommercial}, want: []string{"Ad.ObjectType", "Ad.BuildingType"}, wantNot: []string{"Ad.NewDevelopmentId", "Ad.Rooms"}},
+		{name: "garage", lot: Ad{Category: categoryGarages}, wantNot: []string{"Ad.NewDevelopmentId", "Ad.ObjectType"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := validate(t, tt.lot)
+
+			for _, path := range tt.want {
+				if !paths[path] {
+					t.Errorf("no finding for %s in %v", path, paths)
+				}
+			}
+
+			for _, path := range tt.wantNot {
+				if paths[path] {
+					t.Errorf("unexpected finding for %s", path)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAreaSum(t *testing.T) {
+	tests := []struct {
+		name    string
+		lot     Ad
+		finding bool
+	}{
+		{name: "fits", lot: Ad{Category: categoryFlats, Rooms: "2", Square: float(60), LivingSpace: float(35), KitchenSpace: float(12)}},
+		{name: "too big", lot: Ad{Category: categoryFlats, Rooms: "2", Square: float(45), LivingSpace: float(35), KitchenSpace: float(12)}, finding: true},
+		{name: "no square", lot: Ad{Category: categoryFlats, Rooms: "2", LivingSpace: float(35), KitchenSpace: float(12)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lot := tt.lot
+			lot.ID = "1"
+
+			feed := &Feed{isGet: true, Data: Data{Ad: []Ad{lot, lot, lot, lot, lot, lot, lot, lot, lot, lot, lot}}}
+
+			findings, err := feed.Validate()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			found := false
+			for _, finding := range findings {
+				if finding.Rule == validation.RuleArea && finding.Path == "Ad.TotalArea" && strings.Contains(finding.Message, "KitchenArea") {
+					found = true
+				}
+			}
+
+			if found != tt.finding {
+				t.Fatalf("area sum finding is %v, want %v: %v", found, tt.finding, findings)
+			}
+		})
+	}
+}
+
+func float(value float64) validation.Float {
+	return validation.Float{Value: value, State: validation.StateSet}
+}
@@ -1,25 +1,55 @@
 package cian
 
 import (
+	"bytes"
 	"context"
 	"encoding/xml"
-	"errors"
 	"fmt"
+	"github.com/zfullio/price-placements/v2/decoding"
+	"github.com/zfullio/price-placements/v2/freshness"
+	"github.com/zfullio/price-placements/v2/option"
+	"github.com/zfullio/price-placements/v2/schema"
+	"github.com/zfullio/price-placements/v2/summary"
 	"github.com/zfullio/price-placements/v2/transport"
 	"github.com/zfullio/price-placements/v2/validation"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 )
 
+const (
+	platformName = "cian"
+
+	lotElement = "object"
+
+	flatRoomsFreeLayout = 7
+	flatRoomsStudio     = 9
+
+	categoryNewBuildingFlatSale = "newBuildingFlatSale"
+	categoryFlatSale            = "flatSale"
+	categoryFlatRent            = "flatRent"
+	categoryDailyFlatRent       = "dailyFlatRent"
+	categoryRoomSale            = "roomSale"
+	categoryRoomRent            = "roomRent"
+)
+
 type Feed struct {
-	client       *http.Client
+	transport    *transport.Transport
+	logger       *slog.Logger
 	url          string
 	isGet        bool
+	raw          []byte
+	decoded      decoding.Result
+	rules        validation.Rules
+	header       http.Header
+	DecodeMode   decoding.Mode
 	LastModified time.Time
+	Freshness    freshness.Freshness
+	MaxAge       time.Duration
 	Data         Data
 }
 
@@ -28,10 +58,16 @@ type Data struct {
 	Object      []Object `xml:"object"`
 }
 
-func NewFeed(client *http.Client, url string) *Feed {
+func NewFeed(client *http.Client, url string, opts ...option.Option) *Feed {
+	options := option.Apply(opts...)
+
 	return &Feed{
-		client: client,
-		url:    url,
+		transport:  transport.New(client, options.Transport...),
+		logger:     options.Logger.With(slog.String("platform", platformName), slog.String("url", url)),
+		url:        url,
+		rules:      options.Rules,
+		DecodeMode: options.DecodeMode,
+		MaxAge:     options.MaxAge,
 	}
 }
 
@@ -44,7 +80,15 @@ type Object struct {
 		Lng float32 `xml:"Lng"`
 	} `xml:"Coordinates"`
 	CadastralNumber string `xml:"CadastralNumber"`
-	Phones          struct {
+	Title           string `xml:"Title"`
+	SubAgent        struct {
+		Email     string `xml:"Email"`
+		Phone     string `xml:"Phone"`
+		FirstName string `xml:"FirstName"`
+		LastName  string `xml:"LastName"`
+		AvatarUrl string `xml:"AvatarUrl"`
+	} `xml:"SubAgent"`
+	Phones struct {
 		PhoneSchema struct {
 			CountryCode string `xml:"CountryCode"`
 			Number      string `xml:"Number"`
@@ -57,20 +101,40 @@ type Object struct {
 	Photos struct {
 		PhotoSchema []PhotoSchema `xml:"PhotoSchema"`
 	} `xml:"Photos"`
-	Category              string  `xml:"Category"`
-	RoomType              string  `xml:"RoomType"`
-	FlatRoomsCount        int64   `xml:"FlatRoomsCount"`
-	TotalArea             float32 `xml:"TotalArea"`
-	LivingArea            float32 `xml:"LivingArea"`
-	KitchenArea           float32 `xml:"KitchenArea"`
-	ProjectDeclarationUrl string  `xml:"ProjectDeclarationUrl"`
-	FloorNumber           int64   `xml:"FloorNumber"`
-	CombinedWcsCount      int64   `xml:"CombinedWcsCount"`
+	Videos struct {
+		VideoSchema []struct {
+			Url string `xml:"Url"`
+		} `xml:"VideoSchema"`
+	} `xml:"Videos"`
+	PublishTerms struct {
+		Terms struct {
+			PublishTermSchema []struct {
+				Services struct {
+					ServicesEnum []string `xml:"ServicesEnum"`
+				} `xml:"Services"`
+			} `xml:"PublishTermSchema"`
+		} `xml:"Terms"`
+	} `xml:"PublishTerms"`
+	Category              string           `xml:"Category"`
+	RoomType              string           `xml:"RoomType"`
+	FlatRoomsCount        int64            `xml:"FlatRoomsCount"`
+	TotalArea             validation.Float `xml:"TotalArea"`
+	LivingArea            validation.Float `xml:"LivingArea"`
+	KitchenArea           validation.Float `xml:"KitchenArea"`
+	ProjectDeclarationUrl string           `xml:"ProjectDeclarationUrl"`
+	FloorNumber           int64            `xml:"FloorNumber"`
+	CombinedWcsCount      int64            `xml:"CombinedWcsCount"`
 	Building              struct {
-		FloorsCount         int64  `xml:"FloorsCount"`
-		MaterialType        string `xml:"MaterialType"`
-		PassengerLiftsCount int64  `xml:"PassengerLiftsCount"`
-		CargoLiftsCount     int64  `xml:"CargoLiftsCount"`
+		FloorsCount         int64   `xml:"FloorsCount"`
+		BuildYear           int64   `xml:"BuildYear"`
+		Type                string  `xml:"Type"`
+		ClassType           string  `xml:"ClassType"`
+		TotalArea           float32 `xml:"TotalArea"`
+		Ventilation         string  `xml:"Ventilation"`
+		Heating             string  `xml:"Heating"`
+		MaterialType        string  `xml:"MaterialType"`
+		PassengerLiftsCount int64   `xml:"PassengerLiftsCount"`
+		CargoLiftsCount     int64   `xml:"CargoLiftsCount"`
 		Parking             struct {
 			Type string `xml:"Type"`
 		} `xml:"Parking"`
@@ -81,10 +145,27 @@ type Object struct {
 		} `xml:"Deadline"`
 	} `xml:"Building"`
 	BargainTerms struct {
-		Price           CustomFloat64 `xml:"Price"`
-		Currency        string        `xml:"Currency"`
-		MortgageAllowed bool          `xml:"MortgageAllowed"`
-		SaleType        string        `xml:"SaleType"`
+		Price             validation.Float `xml:"Price"`
+		PriceType         string           `xml:"PriceType"`
+		Currency          string           `xml:"Currency"`
+		MortgageAllowed   bool             `xml:"MortgageAllowed"`
+		SaleType          string           `xml:"SaleType"`
+		BargainAllowed    bool             `xml:"BargainAllowed"`
+		BargainPrice      validation.Float `xml:"BargainPrice"`
+		BargainConditions string           `xml:"BargainConditions"`
+		Deposit           validation.Float `xml:"Deposit"`
+		ClientFee         validation.Float `xml:"ClientFee"`
+		AgentFee          validation.Float `xml:"AgentFee"`
+		LeaseTermType     string           `xml:"LeaseTermType"`
+		LeaseType         string           `xml:"LeaseType"`
+		PrepayMonths      int64            `xml:"PrepayMonths"`
+		PaymentPeriod     string           `xml:"PaymentPeriod"`
+		VatType           string           `xml:"VatType"`
+		UtilitiesTerms    struct {
+			IncludedInPrice              bool             `xml:"IncludedInPrice"`
+			Price                        validation.Float `xml:"Price"`
+			FlowMetersNotIncludedInPrice bool             `xml:"FlowMetersNotIncludedInPrice"`
+		} `xml:"UtilitiesTerms"`
 	} `xml:"BargainTerms"`
 	JKSchema struct {
 		ID    int32  `xml:"Id"`
@@ -109,7 +190,30 @@ type Object struct {
 			ID            int64  `xml:"Id"`
 		} `xml:"UndergroundInfoSchema"`
 	} `xml:"Undergrounds"`
-	IsApartments bool `xml:"isApartments"`
+	IsApartments        bool             `xml:"IsApartments"`
+	RoomsForSaleCount   int64            `xml:"RoomsForSaleCount"`
+	RoomArea            validation.Float `xml:"RoomArea"`
+	HasFurniture        bool             `xml:"HasFurniture"`
+	HasKitchenFurniture bool             `xml:"HasKitchenFurniture"`
+	HasFridge           bool             `xml:"HasFridge"`
+	HasWasher           bool             `xml:"HasWasher"`
+	HasDishwasher       bool             `xml:"HasDishwasher"`
+	HasTv               bool             `xml:"HasTv"`
+	HasInternet         bool             `xml:"HasInternet"`
+	HasConditioner      bool             `xml:"HasConditioner"`
+	PetsAllowed         bool             `xml:"PetsAllowed"`
+	ChildrenAllowed     bool             `xml:"ChildrenAllowed"`
+	ConditionType       string           `xml:"ConditionType"`
+	Layout              string           `xml:"Layout"`
+	IsOccupied          bool             `xml:"IsOccupied"`
+	FurniturePresence   string           `xml:"FurniturePresence"`
+	AvailableFrom       string           `xml:"AvailableFrom"`
+	CeilingHeightType   string           `xml:"CeilingHeightType"`
+	Land                struct {
+		Area         float32 `xml:"Area"`
+		AreaUnitType string  `xml:"AreaUnitType"`
+		Status       string  `xml:"Status"`
+	} `xml:"Land"`
 }
 
 type PhotoSchema struct {
@@ -117,6 +221,7 @@ type PhotoSchema struct {
 	IsDefault bool   `xml:"IsDefault"`
 }
 
+// Deprecated: use validation.Float, which also tells absent, empty and invalid values apart.
 type CustomFloat64 struct {
 	Float64 float64
 }
@@ -141,106 +246,406 @@ func (f *Feed) Get(ctx context.Context) error {
 		return fmt.Errorf("can't get feed info. Error:%w", err)
 	}
 
-	resp, err := transport.GetResponse(ctx, f.client, f.url)
+	resp, err := f.transport.Get(ctx, f.url)
 	if err != nil {
 		return fmt.Errorf("can't get feed data. Error:%w", err)
 	}
 
 	defer resp.Body.Close()
 
-	responseBody, err := io.ReadAll(resp.Body)
+	responseBody, err := f.transport.ReadBody(resp)
 	if err != nil {
 		return err
 	}
 
-	err = xml.Unmarshal(responseBody, &f)
+	if f.Freshness.Header.IsZero() {
+		f.Freshness.Header, err = freshness.ParseHeader(resp.Header)
+		if err != nil {
+			return err
+		}
+	}
+
+	f.header = resp.Header
+
+	return f.load(responseBody)
+}
+
+func (f *Feed) ReadFile(name string) error {
+	body, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("can't read feed file. Error:%w", err)
+	}
+
+	f.Freshness.FileModTime, err = freshness.FileModTime(name)
+	if err != nil {
+		return err
+	}
+
+	return f.load(body)
+}
+
+// Restore decodes body as if it was fetched with the response header, e.g. from an archived snapshot.
+func (f *Feed) Restore(body []byte, header http.Header) error {
+	var err error
+
+	f.Freshness.Header, err = freshness.ParseHeader(header)
+	if err != nil {
+		return err
+	}
+
+	f.header = header
+
+	return f.load(body)
+}
+
+func (f *Feed) Read(r io.Reader) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("can't read feed. Error:%w", err)
+	}
+
+	return f.load(body)
+}
+
+func (f *Feed) load(body []byte) error {
+	var err error
+
+	f.Data = Data{}
+	f.raw = body
+
+	f.decoded, err = decoding.Decode(body, &f.Data, f.DecodeMode, lotElement)
 	if err != nil {
 		return err
 	}
 
 	f.isGet = true
+	// feed_version is usually a format number, some exports put the generation date there.
+	f.Freshness.Generated = freshness.ParseDate(f.Data.FeedVersion)
+	f.LastModified, _ = f.Freshness.Resolve()
 
-	//TODO Исправить значение f.LastModified
+	f.logger.Debug("feed decoded", slog.Int("bytes", len(body)), slog.Int("lots", f.Lots()),
+		slog.Int("decode_findings", len(f.decoded.Findings)), slog.Time("last_modified", f.LastModified))
 
 	return nil
 }
 
+func (f *Feed) IsStale(now time.Time) bool {
+	return f.Freshness.IsStale(now, f.MaxAge)
+}
+
+func (f *Feed) CheckFreshness(now time.Time) []validation.Finding {
+	return f.rules.Filter(f.Freshness.Check(now, f.MaxAge))
+}
+
+func (f *Feed) Raw() []byte {
+	return f.raw
+}
+
+// Header returns the response header of the last download.
+func (f *Feed) Header() http.Header {
+	return f.header
+}
+
+func (f *Feed) ContentHash() string {
+	return freshness.Hash(f.raw)
+}
+
+func (f *Feed) Lots() int {
+	return len(f.Data.Object)
+}
+
+// lotIDs returns the lot IDs in document order.
+func (f *Feed) lotIDs() []string {
+	ids := make([]string, 0, len(f.Data.Object))
+	for _, lot := range f.Data.Object {
+		ids = append(ids, lot.ExternalId)
+	}
+
+	return ids
+}
+
+func (f *Feed) Summaries() []summary.Lot {
+	lots := make([]summary.Lot, 0, len(f.Data.Object))
+
+	for idx, lot := range f.Data.Object {
+		images := make([]string, 0, len(lot.Photos.PhotoSchema)+1)
+		for _, photo := range lot.Photos.PhotoSchema {
+			images = append(images, photo.FullUrl)
+		}
+
+		if lot.LayoutPhoto.FullUrl != "" {
+			images = append(images, lot.LayoutPhoto.FullUrl)
+		}
+
+		lots = append(lots, summary.Lot{
+			ID:       lot.ExternalId,
+			Title:    summary.Title(lot.Category, lot.Title, lot.Address),
+			Images:   images,
+			Position: f.decoded.Positions.At(lotElement, idx),
+		})
+	}
+
+	return lots
+}
+
+func (f *Feed) Stats() summary.Stats {
+	offers := make([]summary.Offer, 0, len(f.Data.Object))
+
+	for _, lot := range f.Data.Object {
+		building := lot.JKSchema.House.Name
+		if building == "" {
+			building = lot.JKSchema.Name
+		}
+
+		offers = append(offers, summary.Offer{
+			Rooms:      offerRooms(lot.FlatRoomsCount),
+			Building:   building,
+			Section:    lot.JKSchema.House.Flat.SectionNumber,
+			Decoration: lot.Decoration,
+			Price:      lot.BargainTerms.Price.Value,
+			Area:       lot.TotalArea.Value,
+			Photos:     len(lot.Photos.PhotoSchema),
+			HasPlan:    lot.LayoutPhoto.FullUrl != "",
+		})
+	}
+
+	return summary.Compute(offers)
+}
+
+func offerRooms(flatRoomsCount int64) string {
+	parsed := rooms(flatRoomsCount)
+
+	switch parsed.Kind {
+	case validation.RoomsStudio:
+		return summary.RoomsStudio
+	case validation.RoomsFreeLayout:
+		return summary.RoomsFreeLayout
+	default:
+		return summary.Rooms(parsed.Count)
+	}
+}
+
 func (f *Feed) GetInfo(ctx context.Context) error {
-	resp, err := transport.GetOnlyHeader(ctx, f.client, f.url)
+	resp, err := f.transport.Head(ctx, f.url)
 	if err != nil {
 		return err
 	}
 
 	defer resp.Body.Close()
 
-	attributeLastModified := resp.Header.Get(transport.HeaderLastModified)
-	if attributeLastModified != "" {
-		lastModifiedDate, err := time.Parse(time.RFC1123, resp.Header.Get("Last-Modified"))
-		if err != nil {
-			return err
-		}
-		f.LastModified = lastModifiedDate
-	} else {
-		log.Println("Header not contains `Last-Modified`")
+	lastModified, err := freshness.ParseHeader(resp.Header)
+	if err != nil {
+		return err
+	}
+
+	if lastModified.IsZero() {
+		f.logger.DebugContext(ctx, "header not contains `Last-Modified`")
 	}
 
+	f.Freshness.Header = lastModified
+	f.LastModified, _ = f.Freshness.Resolve()
+
 	return nil
 }
 
 func (f *Feed) Check() ([]string, error) {
+	findings, err := f.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return validation.Strings(findings), nil
+}
+
+func (f *Feed) Validate() ([]validation.Finding, error) {
 	if !f.isGet {
-		return nil, errors.New("feed not got")
+		return nil, &transport.NotFetchedError{URL: f.url}
 	}
 
-	results := make([]string, 0)
+	results := make([]validation.Finding, 0, len(f.decoded.Findings))
+	results = append(results, f.decoded.LotFindings(lotElement, f.lotIDs())...)
 
 	if len(f.Data.Object) < 2 {
-		results := append(results, validation.MsgEmptyFeed)
-		return results, nil
+		validation.Add(&results, validation.RuleFeedSize, "", "feed", validation.MsgEmptyFeed)
+		return f.rules.Filter(results), nil
 	}
 
 	if len(f.Data.Object) <= 10 {
-		results = append(results, fmt.Sprintf("feed contains only %v items", len(f.Data.Object)))
-		return results, nil
+		validation.Add(&results, validation.RuleFeedSize, "", "feed", fmt.Sprintf("feed contains only %v items", len(f.Data.Object)))
+		return f.rules.Filter(results), nil
 	}
 	for idx, lot := range f.Data.Object {
+		start := len(results)
 		id := lot.ExternalId
 
-		if lot.ExternalId == "" {
-			results = append(results, fmt.Sprintf("field ExternalId is empty. Position: %v", idx))
-		}
-		validation.CheckStringWithID(id, "object", "Address", lot.Address, &results)
-		validation.CheckStringWithID(id, "object.Phones.PhoneSchema", "CountryCode", lot.Phones.PhoneSchema.CountryCode, &results)
-		validation.CheckStringWithID(id, "object.Phones.PhoneSchema", "Number", lot.Phones.PhoneSchema.Number, &results)
-		validation.CheckStringWithID(id, "object.LayoutPhoto.FullUrl", "IsDefault", lot.LayoutPhoto.FullUrl, &results)
-		validation.CheckStringWithID(id, "object", "Category", lot.Category, &results)
+		validation.CheckFilledWithPos(idx, "object", "ExternalId", lot.ExternalId, &results)
+		validation.CheckFilledWithID(id, "object", "Address", lot.Address, &results)
+		validation.CheckFilledWithID(id, "object.Phones.PhoneSchema", "CountryCode", lot.Phones.PhoneSchema.CountryCode, &results)
+		validation.CheckFilledWithID(id, "object.Phones.PhoneSchema", "Number", lot.Phones.PhoneSchema.Number, &results)
+		validation.CheckFilledWithID(id, "object", "Category", lot.Category, &results)
+		validation.CheckNonZeroWithID(id, "object.BargainTerms", "Price", lot.BargainTerms.Price, &results)
 
 		for idx, photoSchema := range lot.Photos.PhotoSchema {
-			validation.CheckStringWithPos(idx, "object.Photos.PhotoSchema", "FullUrl", photoSchema.FullUrl, &results)
+			validation.CheckFilledWithPos(idx, "object.Photos.PhotoSchema", "FullUrl", photoSchema.FullUrl, &results)
 		}
 
-		validation.CheckZeroWithID(id, "object", "FlatRoomsCount", int(lot.FlatRoomsCount), &results)
-		validation.CheckZeroWithID(id, "object", "TotalArea", int(lot.TotalArea), &results)
-		validation.CheckZeroWithID(id, "object", "FloorNumber", int(lot.FloorNumber), &results)
-		validation.CheckZeroWithID(id, "object.Building", "FloorsCount", int(lot.Building.FloorsCount), &results)
-		validation.CheckZeroWithID(id, "object.Building.Deadline", "Year", int(lot.Building.Deadline.Year), &results)
-		validation.CheckStringWithID(id, "object.Building.Deadline", "Quarter", lot.Building.Deadline.Quarter, &results)
-		validation.CheckZeroWithID(id, "object.BargainTerms.Price", "Price", int(lot.BargainTerms.Price.Float64), &results)
-		validation.CheckZeroWithID(id, "object.JKSchema", "Id", int(lot.JKSchema.ID), &results)
-		validation.CheckStringWithID(id, "object.JKSchema", "Name", lot.JKSchema.Name, &results)
-		validation.CheckZeroWithID(id, "object.JKSchema.House", "Id", int(lot.JKSchema.House.ID), &results)
-		validation.CheckStringWithID(id, "object.JKSchema.House", "Name", lot.JKSchema.House.Name, &results)
-
-		if lot.Building.Deadline.Year < int64(time.Now().Year()) && lot.Building.Deadline.IsComplete == false {
-			results = append(results, fmt.Sprintf("field Building.Deadline is False for %v. InternalID: %v", lot.Building.Deadline.Year, lot.ExternalId))
+		for idx, video := range lot.Videos.VideoSchema {
+			validation.CheckFilledWithPos(idx, "object.Videos.VideoSchema", "Url", video.Url, &results)
 		}
-		if lot.FloorNumber > lot.Building.FloorsCount {
-			results = append(results, fmt.Sprintf("field FloorNumber is greater than Building.FloorsCount. InternalID: %v", lot.ExternalId))
+
+		for _, term := range lot.PublishTerms.Terms.PublishTermSchema {
+			for _, service := range term.Services.ServicesEnum {
+				validation.CheckOneOfWithID(id, "object.PublishTerms.Terms.PublishTermSchema.Services", "ServicesEnum", service, []string{
+					"paid", "highlight", "top3", "premium",
+				}, &results)
+			}
 		}
+
+		if lot.SubAgent.Email != "" || lot.SubAgent.Phone != "" {
+			validation.CheckFilledWithID(id, "object.SubAgent", "FirstName", lot.SubAgent.FirstName, &results)
+		}
+
+		// Objects without a category get the new building checks, which every object got before categories were told apart.
+		switch {
+		case lot.Category == categoryNewBuildingFlatSale, lot.Category == "":
+			checkFlat(lot, &results)
+			checkNewBuilding(lot, &results)
+		case lot.Category == categoryFlatSale, lot.Category == categoryRoomSale:
+			checkFlat(lot, &results)
+		case lot.Category == categoryFlatRent, lot.Category == categoryDailyFlatRent, lot.Category == categoryRoomRent:
+			checkFlat(lot, &results)
+			checkRent(lot, &results)
+		case isCommercial(lot.Category):
+			checkCommercial(lot, &results)
+		}
+
 		if len(lot.Photos.PhotoSchema) < 3 {
-			results = append(results, fmt.Sprintf("field Photos.PhotoSchema contains '%v' items. InternalID: %v", len(lot.Photos.PhotoSchema), lot.ExternalId))
+			validation.Add(&results, validation.RuleImages, id, "object.Photos.PhotoSchema", fmt.Sprintf("field Photos.PhotoSchema contains '%v' items", len(lot.Photos.PhotoSchema)))
 		}
+
+		validation.SetPosition(results[start:], f.decoded.Positions.At(lotElement, idx))
+	}
+
+	return f.rules.Filter(results), nil
+}
+
+func checkFlat(lot Object, results *[]validation.Finding) {
+	id := lot.ExternalId
+
+	validation.CheckNumberWithID(id, "object", "FlatRoomsCount", int(lot.FlatRoomsCount), results)
+	validation.CheckNonZeroWithID(id, "object", "TotalArea", lot.TotalArea, results)
+	validation.CheckAreaSum(id, "object", lot.TotalArea.Value, lot.LivingArea.Value, lot.KitchenArea.Value, results)
+
+	validation.CheckRooms(id, "object", validation.Layout{
+		Field:    "FlatRoomsCount",
+		Rooms:    rooms(lot.FlatRoomsCount),
+		RoomType: lot.RoomType,
+		FlatType: lot.JKSchema.House.Flat.FlatType,
+		Total:    lot.TotalArea.Value,
+	}, results)
+
+	validation.CheckNumberWithID(id, "object", "FloorNumber", int(lot.FloorNumber), results)
+	validation.CheckNumberWithID(id, "object.Building", "FloorsCount", int(lot.Building.FloorsCount), results)
+
+	if lot.Category == categoryRoomSale || lot.Category == categoryRoomRent {
+		validation.CheckNonZeroWithID(id, "object", "RoomArea", lot.RoomArea, results)
+	}
+
+	if lot.FloorNumber > lot.Building.FloorsCount {
+		validation.Add(results, validation.RuleConsistency, id, "object.FloorNumber", "field FloorNumber is greater than Building.FloorsCount")
+	}
+}
+
+func checkNewBuilding(lot Object, results *[]validation.Finding) {
+	id := lot.ExternalId
+
+	validation.CheckFilledWithID(id, "object.LayoutPhoto.FullUrl", "IsDefault", lot.LayoutPhoto.FullUrl, results)
+	validation.CheckNumberWithID(id, "object.Building.Deadline", "Year", int(lot.Building.Deadline.Year), results)
+	validation.CheckFilledWithID(id, "object.Building.Deadline", "Quarter", lot.Building.Deadline.Quarter, results)
+	validation.CheckNumberWithID(id, "object.JKSchema", "Id", int(lot.JKSchema.ID), results)
+	validation.CheckFilledWithID(id, "object.JKSchema", "Name", lot.JKSchema.Name, results)
+	validation.CheckNumberWithID(id, "object.JKSchema.House", "Id", int(lot.JKSchema.House.ID), results)
+	validation.CheckFilledWithID(id, "object.JKSchema.House", "Name", lot.JKSchema.House.Name, results)
+
+	if lot.Building.Deadline.Year < int64(time.Now().Year()) && !lot.Building.Deadline.IsComplete {
+		validation.Add(results, validation.RuleDeadline, id, "object.Building.Deadline", fmt.Sprintf("field Building.Deadline is False for %v", lot.Building.Deadline.Year))
+	}
+}
+
+func checkRent(lot Object, results *[]validation.Finding) {
+	id := lot.ExternalId
+	terms := lot.BargainTerms
+
+	if lot.Category != categoryDailyFlatRent {
+		validation.CheckFilledWithID(id, "object.BargainTerms", "LeaseTermType", terms.LeaseTermType, results)
+		validation.CheckOneOfWithID(id, "object.BargainTerms", "LeaseTermType", terms.LeaseTermType, []string{"longTerm", "fewMonths"}, results)
+	}
+
+	validation.CheckSetWithID(id, "object.BargainTerms", "Deposit", terms.Deposit, results)
+	validation.CheckSetWithID(id, "object.BargainTerms", "ClientFee", terms.ClientFee, results)
+	validation.CheckSetWithID(id, "object.BargainTerms", "AgentFee", terms.AgentFee, results)
+}
+
+func checkCommercial(lot Object, results *[]validation.Finding) {
+	id := lot.ExternalId
+	terms := lot.BargainTerms
+
+	validation.CheckNonZeroWithID(id, "object", "TotalArea", lot.TotalArea, results)
+	validation.CheckOneOfWithID(id, "object.BargainTerms", "VatType", terms.VatType, []string{"included", "notIncluded", "usn"}, results)
+	validation.CheckOneOfWithID(id, "object.BargainTerms", "PriceType", terms.PriceType, []string{"all", "squareMeter", "hectare", "sotka"}, results)
+	validation.CheckOneOfWithID(id, "object.Building", "ClassType", lot.Building.ClassType, []string{"a", "aPlus", "b", "bPlus", "bMinus", "c", "cPlus", "d"}, results)
+
+	if strings.HasSuffix(lot.Category, "Rent") {
+		validation.CheckFilledWithID(id, "object.BargainTerms", "PaymentPeriod", terms.PaymentPeriod, results)
+		validation.CheckOneOfWithID(id, "object.BargainTerms", "PaymentPeriod", terms.PaymentPeriod, []string{"annual", "monthly"}, results)
+		validation.CheckOneOfWithID(id, "object.BargainTerms", "LeaseType", terms.LeaseType, []string{"direct", "sublease"}, results)
+	}
+
+	if lot.Building.FloorsCount != 0 && lot.FloorNumber > lot.Building.FloorsCount {
+		validation.Add(results, validation.RuleConsistency, id, "object.FloorNumber", "field FloorNumber is greater than Building.FloorsCount")
+	}
+}
+
+func isCommercial(category string) bool {
+	for _, prefix := range []string{
+		"office", "shoppingArea", "warehouse", "freeAppointmentObject", "industry", "building", "business", "commercialLand", "garage",
+	} {
+		if strings.HasPrefix(category, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// rooms converts the FlatRoomsCount code, where 6 means six rooms or more.
+func rooms(flatRoomsCount int64) validation.Rooms {
+	switch {
+	case flatRoomsCount == flatRoomsStudio:
+		return validation.Rooms{Kind: validation.RoomsStudio, Raw: strconv.FormatInt(flatRoomsCount, 10)}
+	case flatRoomsCount == flatRoomsFreeLayout:
+		return validation.Rooms{Kind: validation.RoomsFreeLayout, Raw: strconv.FormatInt(flatRoomsCount, 10)}
+	case flatRoomsCount > 0 && flatRoomsCount < flatRoomsFreeLayout:
+		rooms := validation.RoomsFromCount(flatRoomsCount)
+		rooms.AtLeast = flatRoomsCount == flatRoomsFreeLayout-1
+
+		return rooms
+	case flatRoomsCount == 0:
+		return validation.Rooms{}
+	default:
+		return validation.Rooms{Raw: strconv.FormatInt(flatRoomsCount, 10)}
+	}
+}
+
+func (f *Feed) CheckStructure() ([]validation.Finding, error) {
+	if !f.isGet {
+		return nil, &transport.NotFetchedError{URL: f.url}
+	}
+
+	findings, err := schema.Validate(bytes.NewReader(f.raw), Schema())
+	if err != nil {
+		return nil, err
 	}
 
-	return results, nil
+	return f.rules.Filter(findings), nil
 }
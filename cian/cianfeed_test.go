@@ -0,0 +1,121 @@
+package cian
+
+import (
+	"github.com/zfullio/price-placements/v2/validation"
+	"strings"
+	"testing"
+)
+
+// validate checks a feed of eleven copies of the object, the smallest feed Validate checks lot by lot.
+func validate(t *testing.T, lot Object) map[string]bool {
+	t.Helper()
+
+	lot.ExternalId = "1"
+
+	objects := make([]Object, 11)
+	for idx := range objects {
+		objects[idx] = lot
+	}
+
+	feed := &Feed{isGet: true, Data: Data{Object: objects}}
+
+	findings, err := feed.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := make(map[string]bool, len(findings))
+	for _, finding := range findings {
+		paths[finding.Path] = true
+	}
+
+	return paths
+}
+
+func TestValidateCategories(t *testing.T) {
+	flat := []string{"object.FlatRoomsCount", "object.TotalArea", "object.FloorNumber", "object.Building.FloorsCount"}
+	newBuilding := []string{
+		"object.LayoutPhoto.FullUrl.IsDefault", "object.Building.Deadline.Year", "object.Building.Deadline.Quarter",
+		"object.JKSchema.Id", "object.JKSchema.Name", "object.JKSchema.House.Id", "object.JKSchema.House.Name",
+	}
+	rent := []string{"object.BargainTerms.Deposit", "object.BargainTerms.ClientFee", "object.BargainTerms.AgentFee"}
+
+	tests := []struct {
+		category string
+		want     [][]string
+		wantNot  [][]string
+	}{
+		{category: "", want: [][]string{{"object.Category"}, flat, newBuilding}, wantNot: [][]string{rent}},
+		{category: categoryNewBuildingFlatSale, want: [][]string{flat, newBuilding}, wantNot: [][]string{rent}},
+		{category: categoryFlatSale, want: [][]string{flat}, wantNot: [][]string{newBuilding, rent}},
+		{category: categoryRoomSale, want: [][]string{flat, {"object.RoomArea"}}, wantNot: [][]string{newBuilding, rent}},
+		{category: categoryFlatRent, want: [][]string{flat, rent, {"object.BargainTerms.LeaseTermType"}}, wantNot: [][]string{newBuilding}},
+		{category: categoryDailyFlatRent, want: [][]string{flat, rent}, wantNot: [][]string{newBuilding, {"object.BargainTerms.LeaseTermType"}}},
+		{category: categoryRoomRent, want: [][]string{flat, rent, {"object.RoomArea"}}, wantNot: [][]string{newBuilding}},
+		{category: "officeRent", want: [][]string{{"object.TotalArea", "object.BargainTerms.PaymentPeriod"}}, wantNot: [][]string{newBuilding, rent, {"object.FlatRoomsCount"}}},
+		{category: "officeSale", want: [][]string{{"object.TotalArea"}}, wantNot: [][]string{{"object.BargainTerms.PaymentPeriod", "object.FlatRoomsCount"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.category, func(t *testing.T) {
+			paths := validate(t, Object{Category: tt.category})
+
+			for _, group := range tt.want {
+				for _, path := range group {
+					if !paths[path] {
+						t.Errorf("no finding for %s in %v", path, paths)
+					}
+				}
+			}
+
+			for _, group := range tt.wantNot {
+				for _, path := range group {
+					if paths[path] {
+						t.Errorf("unexpected finding for %s", path)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestValidateAreaSum(t *testing.T) {
+	tests := []struct {
+		name    string
+		lot     Object
+		finding bool
+	}{
+		{name: "fits", lot: Object{Category: categoryFlatSale, FlatRoomsCount: 2, TotalArea: float(60), LivingArea: float(35), KitchenArea: float(12)}},
+		{name: "too big", lot: Object{Category: categoryFlatSale, FlatRoomsCount: 2, TotalArea: float(45), LivingArea: float(35), KitchenArea: float(12)}, finding: true},
+		{name: "no total", lot: Object{Category: categoryFlatSale, FlatRoomsCount: 2, LivingArea: float(35), KitchenArea: float(12)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lot := tt.lot
+			lot.ExternalId = "1"
+
+			feed := &Feed{isGet: true, Data: Data{Object: []Object{lot, lot, lot, lot, lot, lot, lot, lot, lot, lot, lot}}}
+
+			findings, err := feed.Validate()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			found := false
+			for _, finding := range findings {
+				if finding.Rule == validation.RuleArea && finding.Path == "object.TotalArea" && strings.Contains(finding.Message, "KitchenArea") {
+					found = true
+				}
+			}
+
+			if found != tt.finding {
+				t.Fatalf("area sum finding is %v, want %v: %v", found, tt.finding, findings)
+			}
+		})
+	}
+}
+
+func float(value float64) validation.Float {
+	return validation.Float{Value: value, State: validation.StateSet}
+}
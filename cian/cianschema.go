@@ -0,0 +1,14 @@
+package cian
+
+import (
+	_ "embed"
+	"github.com/zfullio/price-placements/v2/schema"
+)
+
+//go:embed cianschema.txt
+var schemaText string
+
+// Schema returns the elements the platform documents for the feed.
+func Schema() *schema.Element {
+	return schema.MustParse(schemaText)
+}
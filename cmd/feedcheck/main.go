@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/zfullio/price-placements/v2/decoding"
+	"github.com/zfullio/price-placements/v2/option"
+	"github.com/zfullio/price-placements/v2/platform"
+	"github.com/zfullio/price-placements/v2/report"
+	"github.com/zfullio/price-placements/v2/validation"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// feedcheck validates one feed and exits with status 1 when error findings are found, for use in CI.
+func main() {
+	platformName := flag.String("platform", "", "feed platform: "+strings.Join(platform.Names(), ", "))
+	url := flag.String("url", "", "feed URL")
+	file := flag.String("file", "", "feed file, used instead of url")
+	format := flag.String("format", report.FormatJSON, "report format: json, csv, junit or html")
+	output := flag.String("o", "", "report file, stdout by default")
+	lenient := flag.Bool("lenient", false, "report malformed values instead of failing")
+	structure := flag.Bool("structure", false, "check the feed structure against the platform schema")
+	timeout := flag.Duration("timeout", 5*time.Minute, "feed download timeout")
+	maxSize := flag.Int64("max-size", 512<<20, "maximum feed size in bytes, 0 for no limit")
+	flag.Parse()
+
+	opts := []option.Option{option.WithMaxBodySize(*maxSize)}
+	if *lenient {
+		opts = append(opts, option.WithDecodeMode(decoding.Lenient))
+	}
+
+	feed, err := platform.New(*platformName, http.DefaultClient, *url, opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	title := *url
+
+	switch {
+	case *file != "":
+		title = *file
+		err = feed.ReadFile(*file)
+	case *url != "":
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		err = feed.Get(ctx)
+
+		cancel()
+	default:
+		err = fmt.Errorf("set -url or -file")
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	findings, err := feed.Validate()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *structure {
+		structureFindings, err := feed.CheckStructure()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		findings = append(findings, structureFindings...)
+	}
+
+	out := os.Stdout
+
+	if *output != "" {
+		out, err = os.Create(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	err = report.Write(out, *format, report.FromFeed(title, *platformName, feed, findings))
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, finding := range findings {
+		if finding.Severity == validation.SeverityError {
+			os.Exit(1)
+		}
+	}
+}
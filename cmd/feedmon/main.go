@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"github.com/zfullio/price-placements/v2/daemon"
+	"github.com/zfullio/price-placements/v2/metrics"
+	"github.com/zfullio/price-placements/v2/server"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+func main() {
+	configPath := flag.String("config", "feedmon.json", "path to the config file")
+	once := flag.Bool("once", false, "check every feed once, print the results and exit")
+	listen := flag.String("listen", "", "address of the HTTP API and /metrics, e.g. :8080")
+	debug := flag.Bool("debug", false, "log fetch and decode details")
+	flag.Parse()
+
+	level := slog.LevelInfo
+	if *debug {
+		level = slog.LevelDebug
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+	slog.SetDefault(logger)
+
+	config, err := daemon.LoadConfig(*configPath)
+	if err != nil {
+		logger.Error("can't load config", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	d, err := daemon.New(config, http.DefaultClient)
+	if err != nil {
+		logger.Error("can't create daemon", slog.Any("error", err))
+		os.Exit(1)
+	}
+
+	d.Logger = logger
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *once {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		if err := encoder.Encode(d.CheckAll(ctx)); err != nil {
+			logger.Error("can't encode results", slog.Any("error", err))
+		}
+
+		return
+	}
+
+	collector := metrics.NewCollector()
+	d.Instrument(collector)
+
+	if *listen != "" {
+		handler := server.New(http.DefaultClient, d)
+		handler.Metrics = collector
+		handler.Logger = logger
+		handler.MaxBodySize = config.MaxBodySize
+
+		if config.ReadTimeout.Duration > 0 {
+			handler.ReadTimeout = config.ReadTimeout.Duration
+		}
+
+		api := &http.Server{Addr: *listen, Handler: handler.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+		go func() {
+			if err := api.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+				logger.Error("can't serve api", slog.Any("error", err))
+				os.Exit(1)
+			}
+		}()
+
+		defer api.Shutdown(context.Background())
+	}
+
+	if err := d.Run(ctx); err != nil {
+		logger.Error("daemon stopped", slog.Any("error", err))
+	}
+}
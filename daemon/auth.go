@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"fmt"
+	"github.com/zfullio/price-placements/v2/option"
+	"github.com/zfullio/price-placements/v2/transport"
+	"os"
+	"strings"
+)
+
+const (
+	AuthBasic  = "basic"
+	AuthBearer = "bearer"
+	AuthHeader = "header"
+	AuthQuery  = "query"
+)
+
+// AuthConfig describes how a feed source is authenticated. A secret written fully as "${FEED_TOKEN}"
+// is read from the environment variable, so it stays out of the config file; other values are literal.
+type AuthConfig struct {
+	Type     string `json:"type"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Token    string `json:"token"`
+	// Name is the header or query parameter carrying Value.
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	CertFile string `json:"cert_file"`
+	KeyFile  string `json:"key_file"`
+	CAFile   string `json:"ca_file"`
+}
+
+func (a *AuthConfig) options() ([]option.Option, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	opts := make([]option.Option, 0, 2)
+
+	secrets := make([]string, 0, 4)
+	for _, value := range []string{a.Username, a.Password, a.Token, a.Value} {
+		secret, err := expandSecret(value)
+		if err != nil {
+			return nil, err
+		}
+
+		secrets = append(secrets, secret)
+	}
+
+	username, password, token, value := secrets[0], secrets[1], secrets[2], secrets[3]
+
+	switch a.Type {
+	case "":
+	case AuthBasic:
+		opts = append(opts, option.WithAuth(transport.BasicAuth{Username: username, Password: password}))
+	case AuthBearer:
+		opts = append(opts, option.WithAuth(transport.BearerToken{Token: token}))
+	case AuthHeader:
+		opts = append(opts, option.WithAuth(transport.HeaderAuth{Name: a.Name, Value: value}))
+	case AuthQuery:
+		opts = append(opts, option.WithAuth(transport.QueryToken{Name: a.Name, Value: value}))
+	default:
+		return nil, fmt.Errorf("unknown auth type %q", a.Type)
+	}
+
+	if a.CertFile != "" || a.KeyFile != "" {
+		config, err := transport.ClientCertificate(a.CertFile, a.KeyFile, a.CAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		opts = append(opts, option.WithTLSConfig(config))
+	}
+
+	return opts, nil
+}
+
+// expandSecret reads a value written as "${NAME}" from the environment variable NAME.
+func expandSecret(value string) (string, error) {
+	name, ok := strings.CutPrefix(value, "${")
+	if !ok {
+		return value, nil
+	}
+
+	name, ok = strings.CutSuffix(name, "}")
+	if !ok || name == "" || strings.ContainsAny(name, "${} ") {
+		return value, nil
+	}
+
+	secret, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s of the feed auth is not set", name)
+	}
+
+	return secret, nil
+}
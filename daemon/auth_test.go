@@ -0,0 +1,64 @@
+package daemon
+
+import (
+	"context"
+	"github.com/zfullio/price-placements/v2/option"
+	"github.com/zfullio/price-placements/v2/transport"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthConfigSecrets(t *testing.T) {
+	t.Setenv("FEED_TOKEN", "from-env")
+
+	tests := []struct {
+		name   string
+		config AuthConfig
+		want   string
+	}{
+		{name: "literal dollars", config: AuthConfig{Type: AuthQuery, Name: "token", Value: "pa$$word"}, want: "pa$$word"},
+		{name: "literal variable", config: AuthConfig{Type: AuthQuery, Name: "token", Value: "abc$def"}, want: "abc$def"},
+		{name: "dollar name", config: AuthConfig{Type: AuthQuery, Name: "token", Value: "$FEED_TOKEN"}, want: "$FEED_TOKEN"},
+		{name: "embedded reference", config: AuthConfig{Type: AuthQuery, Name: "token", Value: "x${FEED_TOKEN}"}, want: "x${FEED_TOKEN}"},
+		{name: "environment", config: AuthConfig{Type: AuthQuery, Name: "token", Value: "${FEED_TOKEN}"}, want: "from-env"},
+		{name: "basic", config: AuthConfig{Type: AuthBasic, Username: "user", Password: "pa$$word"}, want: "pa$$word"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+
+			server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				got = r.URL.Query().Get("token")
+				if _, password, ok := r.BasicAuth(); ok {
+					got = password
+				}
+			}))
+			defer server.Close()
+
+			opts, err := tt.config.options()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			response, err := transport.New(nil, option.Apply(opts...).Transport...).Get(context.Background(), server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			response.Body.Close()
+
+			if got != tt.want {
+				t.Fatalf("got secret %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthConfigUnsetVariable(t *testing.T) {
+	config := AuthConfig{Type: AuthBearer, Token: "${FEED_TOKEN_UNSET}"}
+	if _, err := config.options(); err == nil {
+		t.Fatal("no error")
+	}
+}
@@ -0,0 +1,209 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/zfullio/price-placements/v2/archive"
+	"github.com/zfullio/price-placements/v2/decoding"
+	"github.com/zfullio/price-placements/v2/notify"
+	"github.com/zfullio/price-placements/v2/platform"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	ProfileDefault = "default"
+	ProfileFull    = "full"
+
+	defaultConcurrency = 4
+	defaultRetryDelay  = 10 * time.Second
+	defaultMaxBodySize = 512 << 20
+
+	// flushTimeout limits sending the held back notifications.
+	flushTimeout = 30 * time.Second
+)
+
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string like \"24h\". Error:%w", err)
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+
+	d.Duration = duration
+
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// Profile selects the checks run for a feed.
+type Profile struct {
+	Lenient   bool     `json:"lenient"`
+	Structure bool     `json:"structure"`
+	Freshness bool     `json:"freshness"`
+	MaxAge    Duration `json:"max_age"`
+	// Disabled lists rules whose findings are dropped.
+	Disabled []string `json:"disabled"`
+}
+
+func (p Profile) decodeMode() decoding.Mode {
+	if p.Lenient {
+		return decoding.Lenient
+	}
+
+	return decoding.Strict
+}
+
+// ArchiveConfig keeps every downloaded feed body so past publications can be inspected.
+type ArchiveConfig struct {
+	Dir       string   `json:"dir"`
+	MaxAge    Duration `json:"max_age"`
+	MaxCount  int      `json:"max_count"`
+	KeepDaily int      `json:"keep_daily"`
+}
+
+func (c ArchiveConfig) retention() archive.Retention {
+	return archive.Retention{MaxAge: c.MaxAge.Duration, MaxCount: c.MaxCount, KeepDaily: c.KeepDaily}
+}
+
+type FeedConfig struct {
+	// Name identifies the feed in the API, stored results and the archive, it can't contain '/'.
+	Name     string      `json:"name"`
+	Platform string      `json:"platform"`
+	URL      string      `json:"url"`
+	Schedule string      `json:"schedule"`
+	Profile  string      `json:"profile"`
+	Auth     *AuthConfig `json:"auth"`
+}
+
+type Config struct {
+	Concurrency   int                `json:"concurrency"`
+	Storage       string             `json:"storage"`
+	Timeout       Duration           `json:"timeout"`
+	ReadTimeout   Duration           `json:"read_timeout"`
+	MaxBodySize   int64              `json:"max_body_size"`
+	Retries       int                `json:"retries"`
+	RetryDelay    Duration           `json:"retry_delay"`
+	UnchangedFor  Duration           `json:"unchanged_for"`
+	RunOnStart    bool               `json:"run_on_start"`
+	Profiles      map[string]Profile `json:"profiles"`
+	Notifications *notify.Config     `json:"notifications"`
+	Archive       *ArchiveConfig     `json:"archive"`
+	Feeds         []FeedConfig       `json:"feeds"`
+}
+
+func LoadConfig(name string) (Config, error) {
+	var config Config
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return config, fmt.Errorf("can't read config. Error:%w", err)
+	}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("can't parse config. Error:%w", err)
+	}
+
+	return config, config.normalize()
+}
+
+func (c *Config) normalize() error {
+	if c.Concurrency <= 0 {
+		c.Concurrency = defaultConcurrency
+	}
+
+	if c.RetryDelay.Duration <= 0 {
+		c.RetryDelay.Duration = defaultRetryDelay
+	}
+
+	if c.MaxBodySize <= 0 {
+		c.MaxBodySize = defaultMaxBodySize
+	}
+
+	if c.Profiles == nil {
+		c.Profiles = make(map[string]Profile)
+	}
+
+	if _, ok := c.Profiles[ProfileDefault]; !ok {
+		c.Profiles[ProfileDefault] = Profile{}
+	}
+
+	if _, ok := c.Profiles[ProfileFull]; !ok {
+		c.Profiles[ProfileFull] = Profile{Structure: true, Freshness: true}
+	}
+
+	names := make(map[string]bool)
+	errs := make([]error, 0)
+
+	if c.Archive != nil && c.Archive.Dir == "" {
+		errs = append(errs, errors.New("archive has no dir"))
+	}
+
+	for idx := range c.Feeds {
+		feed := &c.Feeds[idx]
+
+		if err := checkName(feed.Name); err != nil {
+			errs = append(errs, fmt.Errorf("feed #%d %s. Error:%w", idx+1, feed.URL, err))
+		}
+
+		if feed.Profile == "" {
+			feed.Profile = ProfileDefault
+		}
+
+		if names[feed.Name] {
+			errs = append(errs, fmt.Errorf("feed %s is listed twice", feed.Name))
+		}
+
+		names[feed.Name] = true
+
+		if feed.URL == "" {
+			errs = append(errs, fmt.Errorf("feed %s has no url", feed.Name))
+		}
+
+		if !slices.Contains(platform.Names(), feed.Platform) {
+			errs = append(errs, fmt.Errorf("feed %s has unknown platform %q", feed.Name, feed.Platform))
+		}
+
+		if _, ok := c.Profiles[feed.Profile]; !ok {
+			errs = append(errs, fmt.Errorf("feed %s has unknown profile %q", feed.Name, feed.Profile))
+		}
+
+		if _, err := ParseSchedule(feed.Schedule); err != nil {
+			errs = append(errs, fmt.Errorf("feed %s. Error:%w", feed.Name, err))
+		}
+
+		if _, err := feed.Auth.options(); err != nil {
+			errs = append(errs, fmt.Errorf("feed %s. Error:%w", feed.Name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkName makes sure the feed name can be a segment of the API paths.
+func checkName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("feed has no name")
+	case name == "." || name == "..":
+		return fmt.Errorf("feed name %q is reserved", name)
+	case strings.ContainsAny(name, "/\\?#"):
+		return fmt.Errorf("feed name %q must not contain '/', '\\', '?' or '#'", name)
+	}
+
+	return nil
+}
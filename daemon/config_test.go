@@ -0,0 +1,41 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigNormalize(t *testing.T) {
+	feed := func(name string) FeedConfig {
+		return FeedConfig{Name: name, Platform: "avito", URL: "https://example.com/feed.xml", Schedule: "@hourly"}
+	}
+
+	tests := []struct {
+		name  string
+		feeds []FeedConfig
+		err   string
+	}{
+		{name: "valid", feeds: []FeedConfig{feed("avito-main"), feed("avito second")}},
+		{name: "no name", feeds: []FeedConfig{feed("")}, err: "has no name"},
+		{name: "slash", feeds: []FeedConfig{feed("https://example.com/feed.xml")}, err: "must not contain"},
+		{name: "parent", feeds: []FeedConfig{feed("..")}, err: "is reserved"},
+		{name: "duplicate", feeds: []FeedConfig{feed("avito"), feed("avito")}, err: "listed twice"},
+		{name: "platform", feeds: []FeedConfig{{Name: "x", Platform: "olx", URL: "https://example.com", Schedule: "@hourly"}}, err: "unknown platform"},
+		{name: "profile", feeds: []FeedConfig{{Name: "x", Platform: "avito", URL: "https://example.com", Schedule: "@hourly", Profile: "strict"}}, err: "unknown profile"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Config{Feeds: tt.feeds}
+
+			err := config.normalize()
+
+			switch {
+			case tt.err == "" && err != nil:
+				t.Fatalf("unexpected error %v", err)
+			case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+				t.Fatalf("got error %v, want %q", err, tt.err)
+			}
+		})
+	}
+}
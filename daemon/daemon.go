@@ -0,0 +1,612 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/zfullio/price-placements/v2/archive"
+	"github.com/zfullio/price-placements/v2/freshness"
+	"github.com/zfullio/price-placements/v2/metrics"
+	"github.com/zfullio/price-placements/v2/notify"
+	"github.com/zfullio/price-placements/v2/option"
+	"github.com/zfullio/price-placements/v2/platform"
+	"github.com/zfullio/price-placements/v2/validation"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+type offerDatesChecker interface {
+	CheckOfferDates(now time.Time, maxAge time.Duration) []validation.Finding
+}
+
+type Status struct {
+	Feed     string    `json:"feed"`
+	Platform string    `json:"platform"`
+	URL      string    `json:"url"`
+	Schedule string    `json:"schedule"`
+	Profile  string    `json:"profile"`
+	Running  bool      `json:"running"`
+	Next     time.Time `json:"next"`
+	Last     *Result   `json:"last,omitempty"`
+}
+
+type entry struct {
+	config   FeedConfig
+	schedule Schedule
+	next     time.Time
+	running  bool
+	last     *Result
+}
+
+// Daemon checks the configured feeds on their schedules.
+type Daemon struct {
+	Logger   *slog.Logger
+	config   Config
+	client   *http.Client
+	store    *Store
+	archive  *archive.Archive
+	monitor  *freshness.Monitor
+	metrics  *metrics.Collector
+	notifier *notify.Notifier
+	slots    chan struct{}
+	// wake makes Run recompute its timer, e.g. when notifications were held back.
+	wake    chan struct{}
+	mu      sync.RWMutex
+	entries []*entry
+}
+
+func New(config Config, client *http.Client) (*Daemon, error) {
+	if err := config.normalize(); err != nil {
+		return nil, fmt.Errorf("invalid config. Error:%w", err)
+	}
+
+	d := &Daemon{
+		config:  config,
+		Logger:  slog.Default(),
+		client:  client,
+		monitor: freshness.NewMonitor(config.UnchangedFor.Duration),
+		slots:   make(chan struct{}, config.Concurrency),
+		wake:    make(chan struct{}, 1),
+		entries: make([]*entry, 0, len(config.Feeds)),
+	}
+
+	if config.Storage != "" {
+		d.store = NewStore(config.Storage)
+	}
+
+	if config.Archive != nil {
+		snapshots, err := archive.New(config.Archive.Dir)
+		if err != nil {
+			return nil, err
+		}
+
+		d.archive = snapshots
+	}
+
+	if config.Notifications != nil {
+		notifier, err := notify.New(*config.Notifications, nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid notifications config. Error:%w", err)
+		}
+
+		d.notifier = notifier
+	}
+
+	for _, feed := range config.Feeds {
+		schedule, err := ParseSchedule(feed.Schedule)
+		if err != nil {
+			return nil, err
+		}
+
+		current := &entry{config: feed, schedule: schedule}
+
+		if d.store != nil {
+			last, ok, err := d.store.Latest(feed.Name)
+			if err != nil {
+				return nil, err
+			}
+
+			if ok {
+				current.last = &last
+
+				if d.notifier != nil {
+					d.notifier.Seed(last.check())
+				}
+
+				d.monitor.Restore(feed.Name, freshness.State{
+					Last:    freshness.Observation{Fetched: last.Started, LastModified: last.LastModified, Hash: last.Hash},
+					Changed: last.Changed,
+				})
+			}
+		}
+
+		d.entries = append(d.entries, current)
+	}
+
+	return d, nil
+}
+
+// Instrument records fetch and check metrics of every feed in collector.
+func (d *Daemon) Instrument(collector *metrics.Collector) {
+	client := http.Client{}
+	if d.client != nil {
+		client = *d.client
+	}
+
+	client.Transport = collector.Transport(client.Transport)
+
+	d.client = &client
+	d.metrics = collector
+}
+
+// Run checks feeds when their schedules are due until ctx is cancelled. Notifications held back
+// during quiet hours are sent when the quiet hours end and on shutdown.
+func (d *Daemon) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+
+	defer func() {
+		wg.Wait()
+		d.flush(context.WithoutCancel(ctx))
+	}()
+
+	now := time.Now()
+
+	d.mu.Lock()
+	for _, current := range d.entries {
+		current.next = current.schedule.Next(now)
+		if d.config.RunOnStart {
+			current.next = now
+		}
+	}
+	d.mu.Unlock()
+
+	for {
+		next := d.nextRun()
+		if flush, ok := d.nextFlush(); ok && (next.IsZero() || flush.Before(next)) {
+			next = flush
+		}
+
+		var (
+			timer *time.Timer
+			wait  <-chan time.Time
+		)
+
+		if !next.IsZero() {
+			timer = time.NewTimer(time.Until(next))
+			wait = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+			stopTimer(timer)
+
+			return nil
+		case <-d.wake:
+			stopTimer(timer)
+
+			continue
+		case <-wait:
+		}
+
+		now = time.Now()
+
+		if flush, ok := d.nextFlush(); ok && !flush.After(now) {
+			d.flush(ctx)
+		}
+
+		d.mu.Lock()
+		for _, current := range d.entries {
+			if current.next.After(now) {
+				continue
+			}
+
+			current.next = current.schedule.Next(now)
+
+			if current.running {
+				d.Logger.WarnContext(ctx, "feed is still being checked, run skipped", slog.String("feed", current.config.Name))
+
+				continue
+			}
+
+			current.running = true
+
+			wg.Add(1)
+
+			go func(current *entry) {
+				defer wg.Done()
+
+				d.run(ctx, current)
+			}(current)
+		}
+		d.mu.Unlock()
+	}
+}
+
+func stopTimer(timer *time.Timer) {
+	if timer != nil {
+		timer.Stop()
+	}
+}
+
+func (d *Daemon) nextFlush() (time.Time, bool) {
+	if d.notifier == nil {
+		return time.Time{}, false
+	}
+
+	return d.notifier.NextFlush(time.Now())
+}
+
+func (d *Daemon) flush(ctx context.Context) {
+	if d.notifier == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, flushTimeout)
+	defer cancel()
+
+	if err := d.notifier.Flush(ctx); err != nil {
+		d.Logger.ErrorContext(ctx, "can't send notification", slog.Any("error", err))
+	}
+}
+
+func (d *Daemon) nextRun() time.Time {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var next time.Time
+
+	for _, current := range d.entries {
+		if !current.next.IsZero() && (next.IsZero() || current.next.Before(next)) {
+			next = current.next
+		}
+	}
+
+	return next
+}
+
+// CheckNow checks the feed immediately regardless of its schedule.
+func (d *Daemon) CheckNow(ctx context.Context, name string) (Result, error) {
+	d.mu.Lock()
+
+	idx := slices.IndexFunc(d.entries, func(current *entry) bool { return current.config.Name == name })
+	if idx < 0 {
+		d.mu.Unlock()
+
+		return Result{}, fmt.Errorf("feed %s is not configured", name)
+	}
+
+	current := d.entries[idx]
+	if current.running {
+		d.mu.Unlock()
+
+		return Result{}, fmt.Errorf("feed %s is already being checked", name)
+	}
+
+	current.running = true
+	d.mu.Unlock()
+
+	return d.run(ctx, current), nil
+}
+
+// CheckAll checks every feed once and returns the results in config order.
+func (d *Daemon) CheckAll(ctx context.Context) []Result {
+	results := make([]Result, len(d.config.Feeds))
+
+	var wg sync.WaitGroup
+
+	for idx, feed := range d.config.Feeds {
+		wg.Add(1)
+
+		go func(idx int, name string) {
+			defer wg.Done()
+
+			result, err := d.CheckNow(ctx, name)
+			if err != nil {
+				result = Result{Feed: name, Error: err.Error()}
+			}
+
+			results[idx] = result
+		}(idx, feed.Name)
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func (d *Daemon) run(ctx context.Context, current *entry) Result {
+	select {
+	case d.slots <- struct{}{}:
+	case <-ctx.Done():
+		d.mu.Lock()
+		current.running = false
+		d.mu.Unlock()
+
+		return Result{Feed: current.config.Name, Started: time.Now(), Error: ctx.Err().Error()}
+	}
+
+	result := d.check(ctx, current.config)
+	<-d.slots
+
+	if d.store != nil {
+		if err := d.store.Save(result); err != nil {
+			d.Logger.ErrorContext(ctx, "can't store result", slog.String("feed", result.Feed), slog.Any("error", err))
+		}
+	}
+
+	if d.notifier != nil {
+		if err := d.notifier.Notify(ctx, result.check()); err != nil {
+			d.Logger.ErrorContext(ctx, "can't send notification", slog.String("feed", result.Feed), slog.Any("error", err))
+		}
+
+		select {
+		case d.wake <- struct{}{}:
+		default:
+		}
+	}
+
+	d.mu.Lock()
+	current.running = false
+	current.last = &result
+	d.mu.Unlock()
+
+	return result
+}
+
+func (d *Daemon) check(ctx context.Context, config FeedConfig) (result Result) {
+	profile := d.config.Profiles[config.Profile]
+	started := time.Now()
+	result = Result{Feed: config.Name, Platform: config.Platform, URL: config.URL, Started: started}
+
+	defer func() {
+		result.Duration = Duration{time.Since(started)}
+	}()
+
+	if d.config.Timeout.Duration > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, d.config.Timeout.Duration)
+		defer cancel()
+	}
+
+	auth, err := config.Auth.options()
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	feed, err := platform.New(config.Platform, d.client, config.URL, append([]option.Option{
+		option.WithLogger(d.Logger.With(slog.String("feed", config.Name))),
+		option.WithDecodeMode(profile.decodeMode()),
+		option.WithMaxAge(profile.MaxAge.Duration),
+		option.WithoutRules(profile.Disabled...),
+		option.WithMaxBodySize(d.config.MaxBodySize),
+		option.WithReadTimeout(d.config.ReadTimeout.Duration),
+	}, auth...)...)
+	if err != nil {
+		result.Error = err.Error()
+
+		return result
+	}
+
+	err = d.get(metrics.WithFeed(ctx, config.Name), feed, config.Name)
+	d.archiveFeed(ctx, feed, config, started)
+
+	if err != nil {
+		result.Error = err.Error()
+		d.observe(result, err)
+
+		return result
+	}
+
+	findings, err := d.findings(feed, config, profile, started)
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	state, _ := d.monitor.State(config.Name)
+
+	result.LastModified, _ = feed.Dates().Resolve()
+	result.Changed = state.Changed
+	result.Hash = feed.ContentHash()
+	result.Lots = feed.Lots()
+	result.Findings = slices.DeleteFunc(findings, func(finding validation.Finding) bool {
+		return slices.Contains(profile.Disabled, finding.Rule)
+	})
+
+	d.observe(result, err)
+
+	d.Logger.InfoContext(ctx, "feed checked", slog.String("feed", config.Name), slog.String("platform", config.Platform),
+		slog.Int("lots", result.Lots), slog.Int("findings", len(result.Findings)),
+		slog.Duration("duration", time.Since(started)), slog.String("error", result.Error))
+
+	return result
+}
+
+// archiveFeed keeps the downloaded body, also when it could not be decoded.
+func (d *Daemon) archiveFeed(ctx context.Context, feed platform.Feed, config FeedConfig, fetched time.Time) {
+	if d.archive == nil || feed.Raw() == nil {
+		return
+	}
+
+	lastModified, _ := feed.Dates().Resolve()
+
+	snapshot, err := d.archive.Save(archive.Snapshot{
+		Feed:         config.Name,
+		Platform:     config.Platform,
+		URL:          config.URL,
+		Fetched:      fetched,
+		LastModified: lastModified,
+		Header:       feed.Header(),
+	}, feed.Raw())
+	if err != nil {
+		d.Logger.ErrorContext(ctx, "can't archive feed", slog.String("feed", config.Name), slog.Any("error", err))
+
+		return
+	}
+
+	d.Logger.DebugContext(ctx, "feed archived", slog.String("feed", config.Name),
+		slog.String("hash", snapshot.Hash), slog.Int64("size", snapshot.Size))
+
+	if _, err := d.archive.Prune(d.config.Archive.retention(), time.Now()); err != nil {
+		d.Logger.ErrorContext(ctx, "can't prune archive", slog.Any("error", err))
+	}
+}
+
+// Snapshots lists the archived bodies of the feed, oldest first.
+func (d *Daemon) Snapshots(name string) ([]archive.Snapshot, error) {
+	if d.archive == nil {
+		return nil, errors.New("feeds are not archived, set archive in the config")
+	}
+
+	return d.archive.List(name)
+}
+
+// Snapshot returns the body of the feed published at the moment.
+func (d *Daemon) Snapshot(name string, moment time.Time) (archive.Snapshot, []byte, error) {
+	if d.archive == nil {
+		return archive.Snapshot{}, nil, errors.New("feeds are not archived, set archive in the config")
+	}
+
+	snapshot, err := d.archive.At(name, moment)
+	if err != nil {
+		return snapshot, nil, err
+	}
+
+	body, err := d.archive.Body(snapshot)
+
+	return snapshot, body, err
+}
+
+// get downloads the feed, repeating failed downloads up to the configured number of retries.
+func (d *Daemon) get(ctx context.Context, feed platform.Feed, name string) error {
+	err := feed.Get(ctx)
+
+	for attempt := 1; err != nil && attempt <= d.config.Retries; attempt++ {
+		timer := time.NewTimer(time.Duration(attempt) * d.config.RetryDelay.Duration)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return err
+		case <-timer.C:
+		}
+
+		if d.metrics != nil {
+			d.metrics.ObserveRetry(name)
+		}
+
+		d.Logger.WarnContext(ctx, "feed download failed, retrying", slog.String("feed", name),
+			slog.Int("attempt", attempt), slog.Any("error", err))
+
+		err = feed.Get(ctx)
+	}
+
+	return err
+}
+
+func (d *Daemon) observe(result Result, err error) {
+	if d.metrics != nil {
+		d.metrics.ObserveCheck(result.Feed, result.Platform, result.Lots, result.Findings, result.LastModified, err)
+	}
+}
+
+func (d *Daemon) findings(feed platform.Feed, config FeedConfig, profile Profile, now time.Time) ([]validation.Finding, error) {
+	observed := d.monitor.Observe(config.Name, feed.Dates(), feed.ContentHash(), now)
+
+	findings, err := feed.Validate()
+	if err != nil {
+		return findings, err
+	}
+
+	if profile.Structure {
+		structure, err := feed.CheckStructure()
+		if err != nil {
+			return findings, err
+		}
+
+		findings = append(findings, structure...)
+	}
+
+	if profile.Freshness {
+		findings = append(findings, feed.CheckFreshness(now)...)
+		findings = append(findings, observed...)
+
+		if checker, ok := feed.(offerDatesChecker); ok {
+			findings = append(findings, checker.CheckOfferDates(now, 0)...)
+		}
+	}
+
+	return findings, nil
+}
+
+// Diff compares the two latest stored results of the feed.
+func (d *Daemon) Diff(name string) (Diff, error) {
+	if d.store == nil {
+		return Diff{}, errors.New("results are not stored, set storage in the config")
+	}
+
+	history, err := d.store.History(name, 2)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	switch len(history) {
+	case 0:
+		return Diff{}, fmt.Errorf("feed %s has no stored results", name)
+	case 1:
+		return Compare(Result{Feed: name}, history[0]), nil
+	default:
+		return Compare(history[1], history[0]), nil
+	}
+}
+
+// Status returns the latest state of every feed in config order.
+func (d *Daemon) Status() []Status {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(d.entries))
+	for _, current := range d.entries {
+		statuses = append(statuses, current.status())
+	}
+
+	return statuses
+}
+
+func (d *Daemon) Latest(name string) (Status, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	for _, current := range d.entries {
+		if current.config.Name == name {
+			return current.status(), true
+		}
+	}
+
+	return Status{}, false
+}
+
+func (e *entry) status() Status {
+	status := Status{
+		Feed:     e.config.Name,
+		Platform: e.config.Platform,
+		URL:      e.config.URL,
+		Schedule: e.config.Schedule,
+		Profile:  e.config.Profile,
+		Running:  e.running,
+		Next:     e.next,
+	}
+
+	if e.last != nil {
+		last := *e.last
+		status.Last = &last
+	}
+
+	return status
+}
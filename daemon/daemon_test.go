@@ -0,0 +1,57 @@
+package daemon
+
+import (
+	"context"
+	"github.com/zfullio/price-placements/v2/notify"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRunFlushesNotificationsOnShutdown(t *testing.T) {
+	var sent atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent.Add(1)
+	}))
+	defer server.Close()
+
+	now := time.Now().UTC()
+	quiet := now.Add(-time.Hour).Format("15:04") + "-" + now.Add(time.Hour).Format("15:04")
+
+	d, err := New(Config{Notifications: &notify.Config{
+		Webhooks:   []notify.WebhookConfig{{URL: server.URL}},
+		QuietHours: quiet,
+		TimeZone:   "UTC",
+	}}, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := d.notifier.Notify(context.Background(), notify.Check{Feed: "feed", Time: now, Error: "timeout"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := sent.Load(); got != 0 {
+		t.Fatalf("%v notifications sent during quiet hours", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	go func() {
+		done <- d.Run(ctx)
+	}()
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if got := sent.Load(); got != 1 {
+		t.Fatalf("%v notifications sent on shutdown, want 1", got)
+	}
+}
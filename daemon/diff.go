@@ -0,0 +1,29 @@
+package daemon
+
+import (
+	"github.com/zfullio/price-placements/v2/validation"
+	"time"
+)
+
+// Diff describes how the findings of a feed changed between two checks.
+type Diff struct {
+	Feed       string               `json:"feed"`
+	From       time.Time            `json:"from"`
+	To         time.Time            `json:"to"`
+	LotsBefore int                  `json:"lots_before"`
+	LotsAfter  int                  `json:"lots_after"`
+	Added      []validation.Finding `json:"added"`
+	Resolved   []validation.Finding `json:"resolved"`
+}
+
+func Compare(previous Result, current Result) Diff {
+	return Diff{
+		Feed:       current.Feed,
+		From:       previous.Started,
+		To:         current.Started,
+		LotsBefore: previous.Lots,
+		LotsAfter:  current.Lots,
+		Added:      validation.Difference(current.Findings, previous.Findings),
+		Resolved:   validation.Difference(previous.Findings, current.Findings),
+	}
+}
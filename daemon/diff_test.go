@@ -0,0 +1,34 @@
+package daemon
+
+import (
+	"github.com/zfullio/price-placements/v2/validation"
+	"testing"
+	"time"
+)
+
+func TestCompare(t *testing.T) {
+	empty := validation.Finding{Rule: validation.RuleEmpty, ID: "1", Path: "offer.Price", Message: "field offer.Price is empty"}
+	images := validation.Finding{Rule: validation.RuleImages, ID: "2", Path: "offer.Image", Message: "field Image contains '1' items"}
+	area := validation.Finding{Rule: validation.RuleArea, ID: "3", Path: "offer.TotalArea", Message: "field offer.TotalArea is too small"}
+
+	moved := images
+	moved.Position = validation.Position{Offset: 120, Line: 4, Column: 1}
+
+	previous := Result{Feed: "feed", Started: time.Unix(100, 0), Lots: 10, Findings: []validation.Finding{empty, images, images}}
+	current := Result{Feed: "feed", Started: time.Unix(200, 0), Lots: 12, Findings: []validation.Finding{moved, area}}
+
+	diff := Compare(previous, current)
+
+	if diff.Feed != "feed" || !diff.From.Equal(previous.Started) || !diff.To.Equal(current.Started) ||
+		diff.LotsBefore != 10 || diff.LotsAfter != 12 {
+		t.Fatalf("unexpected diff %+v", diff)
+	}
+
+	if len(diff.Added) != 1 || diff.Added[0] != area {
+		t.Fatalf("added %v, want %v", diff.Added, area)
+	}
+
+	if len(diff.Resolved) != 2 || diff.Resolved[0] != empty || diff.Resolved[1] != images {
+		t.Fatalf("resolved %v, want %v and one of %v", diff.Resolved, empty, images)
+	}
+}
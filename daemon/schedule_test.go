@@ -0,0 +1,54 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"", "* * * *", "* * * * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "* * * 13 *", "* * * * 8",
+		"5-1 * * * *", "*/0 * * * *", "a * * * *", "1-b * * * *", "@every", "@every 1ms", "@yearly",
+	} {
+		if _, err := ParseSchedule(spec); err == nil {
+			t.Errorf("schedule %q parsed", spec)
+		}
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	// 2024-03-01 is a Friday.
+	after := time.Date(2024, 3, 1, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{spec: "* * * * *", want: time.Date(2024, 3, 1, 10, 8, 0, 0, time.UTC)},
+		{spec: "*/15 * * * *", want: time.Date(2024, 3, 1, 10, 15, 0, 0, time.UTC)},
+		{spec: "5,40 * * * *", want: time.Date(2024, 3, 1, 10, 40, 0, 0, time.UTC)},
+		{spec: "0 8-9 * * *", want: time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC)},
+		{spec: "30 9 * * 1-5", want: time.Date(2024, 3, 4, 9, 30, 0, 0, time.UTC)},
+		{spec: "0 12 * * 7", want: time.Date(2024, 3, 3, 12, 0, 0, 0, time.UTC)},
+		{spec: "0 0 31 * *", want: time.Date(2024, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 29 2 *", want: time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{spec: "0 0 15 * 1", want: time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{spec: "@hourly", want: time.Date(2024, 3, 1, 11, 0, 0, 0, time.UTC)},
+		{spec: "@daily", want: time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)},
+		{spec: "@weekly", want: time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{spec: "@every 90m", want: time.Date(2024, 3, 1, 11, 37, 30, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := schedule.Next(after); !got.Equal(tt.want) {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package daemon
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/zfullio/price-placements/v2/notify"
+	"github.com/zfullio/price-placements/v2/validation"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const latestFile = "latest.json"
+
+type Result struct {
+	Feed         string               `json:"feed"`
+	Platform     string               `json:"platform"`
+	URL          string               `json:"url"`
+	Started      time.Time            `json:"started"`
+	Duration     Duration             `json:"duration"`
+	LastModified time.Time            `json:"last_modified"`
+	Changed      time.Time            `json:"changed"`
+	Hash         string               `json:"hash"`
+	Lots         int                  `json:"lots"`
+	Findings     []validation.Finding `json:"findings"`
+	Error        string               `json:"error,omitempty"`
+}
+
+func (r Result) Count(severity validation.Severity) int {
+	count := 0
+
+	for _, finding := range r.Findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (r Result) check() notify.Check {
+	return notify.Check{Feed: r.Feed, Time: r.Started, Lots: r.Lots, Findings: r.Findings, Error: r.Error}
+}
+
+// Store keeps every result as a JSON file in a directory per feed.
+type Store struct {
+	dir string
+}
+
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+func (s *Store) Save(result Result) error {
+	dir, err := s.feedDir(result.Feed)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("can't create result directory. Error:%w", err)
+	}
+
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return fmt.Errorf("can't encode result. Error:%w", err)
+	}
+
+	name := result.Started.UTC().Format("20060102T150405.000000000Z") + ".json"
+	if err := os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+		return fmt.Errorf("can't save result. Error:%w", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, latestFile), data, 0o644); err != nil {
+		return fmt.Errorf("can't save result. Error:%w", err)
+	}
+
+	return nil
+}
+
+func (s *Store) Latest(feed string) (Result, bool, error) {
+	var result Result
+
+	dir, err := s.feedDir(feed)
+	if err != nil {
+		return result, false, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, latestFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return result, false, nil
+	}
+
+	if err != nil {
+		return result, false, fmt.Errorf("can't read result. Error:%w", err)
+	}
+
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, false, fmt.Errorf("can't decode result. Error:%w", err)
+	}
+
+	return result, true, nil
+}
+
+// History returns up to limit stored results of the feed, newest first.
+func (s *Store) History(feed string, limit int) ([]Result, error) {
+	dir, err := s.feedDir(feed)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("can't list results. Error:%w", err)
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && entry.Name() != latestFile && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+
+	sort.Sort(sort.Reverse(sort.StringSlice(names)))
+
+	if limit > 0 && len(names) > limit {
+		names = names[:limit]
+	}
+
+	results := make([]Result, 0, len(names))
+
+	for _, name := range names {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return results, fmt.Errorf("can't read result. Error:%w", err)
+		}
+
+		var result Result
+		if err := json.Unmarshal(data, &result); err != nil {
+			return results, fmt.Errorf("can't decode result %s. Error:%w", name, err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// feedDir returns the directory of the feed results. The name is hex-encoded, so distinct
+// names never share a directory and no name can point outside the store.
+func (s *Store) feedDir(feed string) (string, error) {
+	if feed == "" || feed == "." || feed == ".." {
+		return "", fmt.Errorf("invalid feed name %q", feed)
+	}
+
+	return filepath.Join(s.dir, hex.EncodeToString([]byte(feed))), nil
+}
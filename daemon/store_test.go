@@ -0,0 +1,74 @@
+package daemon
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestStoreFeedNames(t *testing.T) {
+	store := NewStore(t.TempDir())
+	names := []string{"a/b", "a_b", "a b"}
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for idx, name := range names {
+		if err := store.Save(Result{Feed: name, Started: start, Lots: idx}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for idx, name := range names {
+		result, ok, err := store.Latest(name)
+		if err != nil || !ok {
+			t.Fatalf("Latest(%q) = %v, %v", name, ok, err)
+		}
+
+		if result.Feed != name || result.Lots != idx {
+			t.Fatalf("Latest(%q) returned the result of %q", name, result.Feed)
+		}
+	}
+
+	for _, name := range []string{"", ".", ".."} {
+		if err := store.Save(Result{Feed: name, Started: start}); err == nil {
+			t.Errorf("Save of feed %q has no error", name)
+		}
+
+		if _, err := store.History(name, 0); err == nil {
+			t.Errorf("History of feed %q has no error", name)
+		}
+	}
+
+	entries, err := os.ReadDir(store.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != len(names) {
+		t.Fatalf("got %v feed directories, want %v", len(entries), len(names))
+	}
+}
+
+func TestStoreHistory(t *testing.T) {
+	store := NewStore(t.TempDir())
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for idx := 0; idx < 4; idx++ {
+		if err := store.Save(Result{Feed: "avito", Started: start.Add(time.Duration(idx) * time.Hour), Lots: idx}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	history, err := store.History("avito", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(history) != 3 || history[0].Lots != 3 || history[2].Lots != 1 {
+		t.Fatalf("History returned %+v, want the 3 latest results newest first", history)
+	}
+
+	history, err = store.History("cian", 0)
+	if err != nil || len(history) != 0 {
+		t.Fatalf("History of an unknown feed = %v, %v", history, err)
+	}
+}
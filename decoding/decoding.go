@@ -0,0 +1,142 @@
+package decoding
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"github.com/zfullio/price-placements/v2/validation"
+)
+
+// Positions holds source positions of decoded elements by element name, in document order.
+type Positions map[string][]validation.Position
+
+func (p Positions) At(name string, idx int) validation.Position {
+	positions := p[name]
+	if idx < 0 || idx >= len(positions) {
+		return validation.Position{}
+	}
+
+	return positions[idx]
+}
+
+type Mode int
+
+const (
+	// Strict aborts decoding on the first malformed value, like xml.Unmarshal.
+	Strict Mode = iota
+	// Lenient normalizes common value issues and records the remaining ones as findings instead of failing.
+	Lenient
+)
+
+type Result struct {
+	Positions Positions
+	// Findings of the lenient mode are positioned at the start of the innermost enclosing element with a recorded name.
+	Findings []validation.Finding
+}
+
+// LotFindings returns the findings with the ID of their enclosing name element, ids holds the IDs of the elements in document order.
+func (r Result) LotFindings(name string, ids []string) []validation.Finding {
+	index := make(map[int64]int, len(r.Positions[name]))
+	for idx, position := range r.Positions[name] {
+		index[position.Offset] = idx
+	}
+
+	findings := make([]validation.Finding, 0, len(r.Findings))
+
+	for _, finding := range r.Findings {
+		if idx, ok := index[finding.Position.Offset]; ok && idx < len(ids) && finding.Position.IsValid() {
+			finding.ID = ids[idx]
+		}
+
+		findings = append(findings, finding)
+	}
+
+	return findings
+}
+
+type recorder struct {
+	decoder   *xml.Decoder
+	names     map[string]bool
+	positions Positions
+	lenient   *lenient
+	queue     []xml.Token
+}
+
+func (r *recorder) Token() (xml.Token, error) {
+	for {
+		if len(r.queue) > 0 {
+			token := r.queue[0]
+			r.queue = r.queue[1:]
+
+			return token, nil
+		}
+
+		line, column := r.decoder.InputPos()
+		position := validation.Position{Offset: r.decoder.InputOffset(), Line: line, Column: column}
+
+		token, err := r.decoder.RawToken()
+		if err != nil {
+			return token, err
+		}
+
+		if start, ok := token.(xml.StartElement); ok && r.names[start.Name.Local] {
+			r.positions[start.Name.Local] = append(r.positions[start.Name.Local], position)
+		}
+
+		if r.lenient == nil {
+			return token, nil
+		}
+
+		r.queue = r.lenient.filter(token, position)
+	}
+}
+
+// Decode unmarshals data into v like xml.Unmarshal and records the positions of the elements with the given names.
+func Decode(data []byte, v any, mode Mode, names ...string) (Result, error) {
+	rec := &recorder{
+		decoder:   xml.NewDecoder(bytes.NewReader(data)),
+		names:     make(map[string]bool, len(names)),
+		positions: make(Positions, len(names)),
+	}
+
+	for _, name := range names {
+		rec.names[name] = true
+	}
+
+	if mode == Lenient {
+		rec.lenient = newLenient(v, rec.names)
+	}
+
+	err := xml.NewTokenDecoder(rec).Decode(v)
+
+	result := Result{Positions: rec.positions}
+	if rec.lenient != nil {
+		result.Findings = rec.lenient.findings
+	}
+
+	if err != nil {
+		line, column := rec.decoder.InputPos()
+
+		return result, &DecodeError{
+			Position: validation.Position{Offset: rec.decoder.InputOffset(), Line: line, Column: column},
+			Err:      err,
+		}
+	}
+
+	return result, nil
+}
+
+// DecodeError is returned when the feed is not well-formed XML or a value can't be decoded in strict mode.
+type DecodeError struct {
+	Position validation.Position
+	Err      error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("can't decode feed at line %d, column %d (offset %d). Error:%s",
+		e.Position.Line, e.Position.Column, e.Position.Offset, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
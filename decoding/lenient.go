@@ -0,0 +1,353 @@
+package decoding
+
+import (
+	"encoding/xml"
+	"fmt"
+	"github.com/zfullio/price-placements/v2/validation"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// node describes which elements and attributes of the target type hold scalar values.
+type node struct {
+	children map[string]*node
+	attrs    map[string]reflect.Type
+	leaf     reflect.Type
+}
+
+func buildNode(t reflect.Type, visited map[reflect.Type]*node) *node {
+	for t.Kind() == reflect.Pointer || (t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8) {
+		t = t.Elem()
+	}
+
+	if isScalar(t) {
+		return &node{leaf: t}
+	}
+
+	if n, ok := visited[t]; ok {
+		return n
+	}
+
+	n := &node{children: make(map[string]*node), attrs: make(map[string]reflect.Type)}
+	visited[t] = n
+
+	if t.Kind() != reflect.Struct {
+		return n
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Name == "XMLName" {
+			continue
+		}
+
+		tag := field.Tag.Get("xml")
+		if tag == "-" {
+			continue
+		}
+
+		name, flags, _ := strings.Cut(tag, ",")
+		if idx := strings.LastIndex(name, " "); idx >= 0 {
+			name = name[idx+1:]
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		switch {
+		case strings.Contains(flags, "attr"):
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+
+			if isScalar(fieldType) {
+				n.attrs[name] = fieldType
+			}
+		case flags != "":
+			continue
+		default:
+			n.children[name] = buildNode(field.Type, visited)
+		}
+	}
+
+	return n
+}
+
+func isScalar(t reflect.Type) bool {
+	if isUnmarshaler(t) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Bool:
+		return true
+	default:
+		return false
+	}
+}
+
+func isUnmarshaler(t reflect.Type) bool {
+	return t.Kind() != reflect.Interface &&
+		reflect.PointerTo(t).Implements(reflect.TypeOf((*xml.Unmarshaler)(nil)).Elem())
+}
+
+func normalizeBool(value string) string {
+	if validation.IsUndefined(value) {
+		return ""
+	}
+
+	parsed, ok := validation.ParseBool(value)
+	if !ok {
+		return strings.TrimSpace(value)
+	}
+
+	return strconv.FormatBool(parsed)
+}
+
+// normalize returns the value in a form encoding/xml accepts for t and reports whether it is valid.
+func normalize(t reflect.Type, value string) (string, bool) {
+	if isUnmarshaler(t) {
+		if tryUnmarshal(t, value) {
+			return value, true
+		}
+
+		normalized := validation.NormalizeNumber(value)
+
+		return normalized, tryUnmarshal(t, normalized)
+	}
+
+	var err error
+
+	switch t.Kind() {
+	case reflect.Bool:
+		value = normalizeBool(value)
+		if value != "" {
+			_, err = strconv.ParseBool(value)
+		}
+	case reflect.Float32, reflect.Float64:
+		value = validation.NormalizeNumber(value)
+		if value != "" {
+			_, err = strconv.ParseFloat(value, 64)
+		}
+	default:
+		value = validation.NormalizeNumber(value)
+		if value == "" {
+			break
+		}
+
+		if _, err = strconv.ParseInt(value, 10, 64); err == nil {
+			break
+		}
+
+		float, floatErr := strconv.ParseFloat(value, 64)
+		if floatErr == nil && float == float64(int64(float)) {
+			value, err = strconv.FormatInt(int64(float), 10), nil
+		}
+	}
+
+	return value, err == nil
+}
+
+func tryUnmarshal(t reflect.Type, value string) bool {
+	var escaped strings.Builder
+	if err := xml.EscapeText(&escaped, []byte(value)); err != nil {
+		return false
+	}
+
+	decoder := xml.NewDecoder(strings.NewReader("<v>" + escaped.String() + "</v>"))
+
+	token, err := decoder.Token()
+	if err != nil {
+		return false
+	}
+
+	start, ok := token.(xml.StartElement)
+	if !ok {
+		return false
+	}
+
+	unmarshaler, ok := reflect.New(t).Interface().(xml.Unmarshaler)
+	if !ok {
+		return false
+	}
+
+	return unmarshaler.UnmarshalXML(decoder, start) == nil
+}
+
+type frame struct {
+	node *node
+	name string
+	path string
+	// lot is set for elements whose positions are recorded, findings inside them are positioned at their start.
+	lot      bool
+	position validation.Position
+}
+
+// pendingLeaf buffers a scalar element until its end so the value can be checked as a whole.
+type pendingLeaf struct {
+	start    xml.StartElement
+	text     strings.Builder
+	position validation.Position
+	path     string
+	leaf     reflect.Type
+	depth    int
+}
+
+type lenient struct {
+	root     *node
+	lots     map[string]bool
+	stack    []frame
+	pending  *pendingLeaf
+	findings []validation.Finding
+}
+
+func newLenient(v any, lots map[string]bool) *lenient {
+	return &lenient{root: buildNode(reflect.TypeOf(v), make(map[reflect.Type]*node)), lots: lots}
+}
+
+// filter consumes a raw token and returns the tokens to pass on to the decoder.
+func (l *lenient) filter(token xml.Token, position validation.Position) []xml.Token {
+	if l.pending != nil {
+		switch t := token.(type) {
+		case xml.CharData:
+			l.pending.text.Write(t)
+
+			return nil
+		case xml.StartElement:
+			l.pending.depth++
+
+			return nil
+		case xml.EndElement:
+			if l.pending.depth > 0 {
+				l.pending.depth--
+
+				return nil
+			}
+
+			return l.flush(t)
+		default:
+			return nil
+		}
+	}
+
+	switch t := token.(type) {
+	case xml.StartElement:
+		return l.start(t, position)
+	case xml.EndElement:
+		if len(l.stack) > 0 {
+			l.stack = l.stack[:len(l.stack)-1]
+		}
+	}
+
+	return []xml.Token{token}
+}
+
+func (l *lenient) start(t xml.StartElement, position validation.Position) []xml.Token {
+	var current *node
+
+	path := t.Name.Local
+
+	switch {
+	case len(l.stack) == 0:
+		current = l.root
+	case l.stack[len(l.stack)-1].node != nil:
+		parent := l.stack[len(l.stack)-1]
+		path = parent.path + "/" + t.Name.Local
+		current = parent.node.children[t.Name.Local]
+	default:
+		path = l.stack[len(l.stack)-1].path + "/" + t.Name.Local
+	}
+
+	if current != nil && current.leaf != nil {
+		l.pending = &pendingLeaf{start: t.Copy(), position: l.enclosing(position), path: path, leaf: current.leaf}
+
+		return nil
+	}
+
+	l.stack = append(l.stack, frame{node: current, name: t.Name.Local, path: path, lot: l.lots[t.Name.Local], position: position})
+
+	if current != nil && len(current.attrs) > 0 {
+		t = l.normalizeAttrs(t, current, path, l.enclosing(position))
+	}
+
+	return []xml.Token{t}
+}
+
+// enclosing returns the position of the innermost open lot element, or position outside of lots.
+func (l *lenient) enclosing(position validation.Position) validation.Position {
+	for idx := len(l.stack) - 1; idx >= 0; idx-- {
+		if l.stack[idx].lot {
+			return l.stack[idx].position
+		}
+	}
+
+	return position
+}
+
+func (l *lenient) normalizeAttrs(t xml.StartElement, current *node, path string, position validation.Position) xml.StartElement {
+	attrs := make([]xml.Attr, 0, len(t.Attr))
+
+	for _, attr := range t.Attr {
+		attrType, ok := current.attrs[attr.Name.Local]
+		if !ok {
+			attrs = append(attrs, attr)
+
+			continue
+		}
+
+		value, valid := normalize(attrType, attr.Value)
+		if !valid {
+			l.addFinding(path+"/@"+attr.Name.Local, attr.Value, attrType, position)
+
+			continue
+		}
+
+		attrs = append(attrs, xml.Attr{Name: attr.Name, Value: value})
+	}
+
+	t.Attr = attrs
+
+	return t
+}
+
+func (l *lenient) flush(end xml.EndElement) []xml.Token {
+	pending := l.pending
+	l.pending = nil
+
+	value, valid := normalize(pending.leaf, pending.text.String())
+	if !valid {
+		l.addFinding(pending.path, pending.text.String(), pending.leaf, pending.position)
+
+		return nil
+	}
+
+	return []xml.Token{pending.start, xml.CharData(value), end}
+}
+
+func (l *lenient) addFinding(path string, value string, t reflect.Type, position validation.Position) {
+	l.findings = append(l.findings, validation.Finding{
+		Severity: validation.RuleSeverity(validation.RuleDecode),
+		Rule:     validation.RuleDecode,
+		Path:     path,
+		Message:  fmt.Sprintf("field %s has invalid %s value '%s'", path, typeName(t), strings.TrimSpace(value)),
+		Position: position,
+	})
+}
+
+func typeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Float32, reflect.Float64:
+		return "decimal"
+	case reflect.Struct:
+		return t.Name()
+	default:
+		return "integer"
+	}
+}
@@ -0,0 +1,126 @@
+package decoding
+
+import (
+	"errors"
+	"github.com/zfullio/price-placements/v2/validation"
+	"testing"
+)
+
+type testFeed struct {
+	Date  string      `xml:"date"`
+	Count int         `xml:"count"`
+	Offer []testOffer `xml:"offer"`
+}
+
+type testOffer struct {
+	ID      string  `xml:"id,attr"`
+	Floor   int     `xml:"floor,attr"`
+	Price   float64 `xml:"price"`
+	Rooms   int64   `xml:"rooms"`
+	NewFlat bool    `xml:"new-flat"`
+}
+
+func TestLenientNormalizes(t *testing.T) {
+	tests := []struct {
+		name string
+		xml  string
+		want testOffer
+	}{
+		{name: "spaces and comma", xml: `<offer id="1"><price>1 000,5</price></offer>`, want: testOffer{ID: "1", Price: 1000.5}},
+		{name: "thousand dots", xml: `<offer id="1"><price>1.234,5</price></offer>`, want: testOffer{ID: "1", Price: 1234.5}},
+		{name: "integer float", xml: `<offer id="1"><rooms>2.0</rooms></offer>`, want: testOffer{ID: "1", Rooms: 2}},
+		{name: "russian boolean", xml: `<offer id="1"><new-flat>да</new-flat></offer>`, want: testOffer{ID: "1", NewFlat: true}},
+		{name: "undefined boolean", xml: `<offer id="1"><new-flat>-</new-flat></offer>`, want: testOffer{ID: "1"}},
+		{name: "attribute", xml: `<offer id="1" floor=" 3 "></offer>`, want: testOffer{ID: "1", Floor: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var feed testFeed
+
+			result, err := Decode([]byte("<feed>"+tt.xml+"</feed>"), &feed, Lenient, "offer")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(result.Findings) != 0 {
+				t.Fatalf("unexpected findings %v", result.Findings)
+			}
+
+			if len(feed.Offer) != 1 || feed.Offer[0] != tt.want {
+				t.Fatalf("got %+v, want %+v", feed.Offer, tt.want)
+			}
+		})
+	}
+}
+
+func TestLenientFindings(t *testing.T) {
+	data := "<feed>\n<count>many</count>\n" +
+		"<offer id=\"1\">\n  <price>10</price>\n</offer>\n" +
+		"<offer id=\"2\" floor=\"first\">\n  <price>free</price>\n  <rooms>2.5</rooms>\n</offer>\n" +
+		"</feed>"
+
+	var feed testFeed
+
+	result, err := Decode([]byte(data), &feed, Lenient, "offer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(feed.Offer) != 2 || feed.Offer[1].Price != 0 {
+		t.Fatalf("unexpected offers %+v", feed.Offer)
+	}
+
+	lot := result.Positions.At("offer", 1)
+
+	want := []struct {
+		path     string
+		id       string
+		position validation.Position
+	}{
+		{path: "feed/count", position: validation.Position{Offset: 7, Line: 2, Column: 1}},
+		{path: "feed/offer/@floor", id: "2", position: lot},
+		{path: "feed/offer/price", id: "2", position: lot},
+		{path: "feed/offer/rooms", id: "2", position: lot},
+	}
+
+	findings := result.LotFindings("offer", []string{"1", "2"})
+	if len(findings) != len(want) {
+		t.Fatalf("got findings %v", findings)
+	}
+
+	for idx, finding := range findings {
+		if finding.Rule != validation.RuleDecode || finding.Path != want[idx].path ||
+			finding.ID != want[idx].id || finding.Position != want[idx].position {
+			t.Fatalf("finding %d is %+v, want %+v", idx, finding, want[idx])
+		}
+	}
+}
+
+func TestStrictFails(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		line int
+	}{
+		{name: "value", body: "<feed>\n<offer><price>free</price></offer></feed>", line: 2},
+		{name: "malformed", body: "<feed>\n<offer>\n<price>1</offer></feed>", line: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var feed testFeed
+
+			_, err := Decode([]byte(tt.body), &feed, Strict, "offer")
+
+			var decodeErr *DecodeError
+			if !errors.As(err, &decodeErr) {
+				t.Fatalf("got %v, want a DecodeError", err)
+			}
+
+			if decodeErr.Position.Line != tt.line {
+				t.Errorf("error is on line %v, want %v", decodeErr.Position.Line, tt.line)
+			}
+		})
+	}
+}
@@ -1,119 +1,146 @@
 package domclick
 
 import (
+	"bytes"
 	"context"
 	"encoding/xml"
-	"errors"
 	"fmt"
+	"github.com/zfullio/price-placements/v2/decoding"
+	"github.com/zfullio/price-placements/v2/freshness"
+	"github.com/zfullio/price-placements/v2/option"
+	"github.com/zfullio/price-placements/v2/schema"
+	"github.com/zfullio/price-placements/v2/summary"
 	"github.com/zfullio/price-placements/v2/transport"
 	"github.com/zfullio/price-placements/v2/validation"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"strconv"
 	"time"
 )
 
+const (
+	platformName = "domclick"
+
+	complexElement  = "complex"
+	buildingElement = "building"
+	flatElement     = "flat"
+)
+
 type Feed struct {
-	client       *http.Client
+	transport    *transport.Transport
+	logger       *slog.Logger
 	url          string
 	isGet        bool
+	raw          []byte
+	decoded      decoding.Result
+	rules        validation.Rules
+	header       http.Header
+	DecodeMode   decoding.Mode
 	LastModified time.Time
+	Freshness    freshness.Freshness
+	MaxAge       time.Duration
 	Data         Data
 }
 
 type Data struct {
-	XMLName xml.Name `xml:"complexes"`
-	Complex struct {
-		ID        string `xml:"id"`
-		Name      string `xml:"name"`
-		Latitude  string `xml:"latitude"`
-		Longitude string `xml:"longitude"`
-		Address   string `xml:"address"`
-		Images    struct {
-			Image []string `xml:"image"`
-		} `xml:"images"`
-		DescriptionMain struct {
+	XMLName xml.Name  `xml:"complexes"`
+	Complex []Complex `xml:"complex"`
+}
+
+type Complex struct {
+	ID        string `xml:"id"`
+	Name      string `xml:"name"`
+	Latitude  string `xml:"latitude"`
+	Longitude string `xml:"longitude"`
+	Address   string `xml:"address"`
+	Images    struct {
+		Image []string `xml:"image"`
+	} `xml:"images"`
+	DescriptionMain struct {
+		Title string `xml:"title"`
+		Text  string `xml:"text"`
+	} `xml:"description_main"`
+	Infrastructure struct {
+		Parking      string `xml:"parking"`
+		Security     string `xml:"security"`
+		FencedArea   string `xml:"fenced_area"`
+		SportsGround string `xml:"sports_ground"`
+		Playground   string `xml:"playground"`
+		School       string `xml:"school"`
+		Kindergarten string `xml:"kindergarten"`
+	} `xml:"infrastructure"`
+	ProfitsMain struct {
+		ProfitMain []struct {
 			Title string `xml:"title"`
 			Text  string `xml:"text"`
-		} `xml:"description_main"`
-		Infrastructure struct {
-			Parking      string `xml:"parking"`
-			Security     string `xml:"security"`
-			FencedArea   string `xml:"fenced_area"`
-			SportsGround string `xml:"sports_ground"`
-			Playground   string `xml:"playground"`
-			School       string `xml:"school"`
-			Kindergarten string `xml:"kindergarten"`
-		} `xml:"infrastructure"`
-		ProfitsMain struct {
-			ProfitMain []struct {
-				Title string `xml:"title"`
-				Text  string `xml:"text"`
-				Image string `xml:"image"`
-			} `xml:"profit_main"`
-		} `xml:"profits_main"`
-		ProfitsSecondary struct {
-			ProfitSecondary []struct {
-				Title string `xml:"title"`
-				Text  string `xml:"text"`
-				Image string `xml:"image"`
-			} `xml:"profit_secondary"`
-		} `xml:"profits_secondary"`
-		Buildings struct {
-			Building []struct {
-				ID            string `xml:"id"`
-				Fz214         string `xml:"fz_214"`
-				Name          string `xml:"name"`
-				Floors        int64  `xml:"floors"`
-				BuildingState string `xml:"building_state"`
-				BuiltYear     int64  `xml:"built_year"`
-				ReadyQuarter  int64  `xml:"ready_quarter"`
-				BuildingType  string `xml:"building_type"`
-				Image         string `xml:"image"`
-				Flats         struct {
-					Flat []Flat `xml:"flat"`
-				} `xml:"flats"`
-			} `xml:"building"`
-		} `xml:"buildings"`
-		SalesInfo struct {
-			SalesPhone              string `xml:"sales_phone"`
-			ResponsibleOfficerPhone string `xml:"responsible_officer_phone"`
-			SalesAddress            string `xml:"sales_address"`
-			SalesLatitude           string `xml:"sales_latitude"`
-			SalesLongitude          string `xml:"sales_longitude"`
-			Timezone                string `xml:"timezone"`
-			WorkDays                struct {
-				WorkDay []struct {
-					Day     string `xml:"day"`
-					OpenAt  string `xml:"open_at"`
-					CloseAt string `xml:"close_at"`
-				} `xml:"work_day"`
-			} `xml:"work_days"`
-		} `xml:"sales_info"`
-		Developer struct {
-			ID    string `xml:"id"`
-			Name  string `xml:"name"`
-			Phone string `xml:"phone"`
-			Site  string `xml:"site"`
-			Logo  string `xml:"logo"`
-		} `xml:"developer"`
-	} `xml:"complex"`
+			Image string `xml:"image"`
+		} `xml:"profit_main"`
+	} `xml:"profits_main"`
+	ProfitsSecondary struct {
+		ProfitSecondary []struct {
+			Title string `xml:"title"`
+			Text  string `xml:"text"`
+			Image string `xml:"image"`
+		} `xml:"profit_secondary"`
+	} `xml:"profits_secondary"`
+	Buildings struct {
+		Building []Building `xml:"building"`
+	} `xml:"buildings"`
+	SalesInfo struct {
+		SalesPhone              string `xml:"sales_phone"`
+		ResponsibleOfficerPhone string `xml:"responsible_officer_phone"`
+		SalesAddress            string `xml:"sales_address"`
+		SalesLatitude           string `xml:"sales_latitude"`
+		SalesLongitude          string `xml:"sales_longitude"`
+		Timezone                string `xml:"timezone"`
+		WorkDays                struct {
+			WorkDay []struct {
+				Day     string `xml:"day"`
+				OpenAt  string `xml:"open_at"`
+				CloseAt string `xml:"close_at"`
+			} `xml:"work_day"`
+		} `xml:"work_days"`
+	} `xml:"sales_info"`
+	Developer struct {
+		ID    string `xml:"id"`
+		Name  string `xml:"name"`
+		Phone string `xml:"phone"`
+		Site  string `xml:"site"`
+		Logo  string `xml:"logo"`
+	} `xml:"developer"`
+}
+
+type Building struct {
+	ID            string `xml:"id"`
+	Fz214         string `xml:"fz_214"`
+	Name          string `xml:"name"`
+	Floors        int64  `xml:"floors"`
+	BuildingState string `xml:"building_state"`
+	BuiltYear     int64  `xml:"built_year"`
+	ReadyQuarter  int64  `xml:"ready_quarter"`
+	BuildingType  string `xml:"building_type"`
+	Image         string `xml:"image"`
+	Flats         struct {
+		Flat []Flat `xml:"flat"`
+	} `xml:"flats"`
 }
 
 type Flat struct {
-	FlatID      string  `xml:"flat_id"`
-	Apartment   string  `xml:"apartment"`
-	Floor       int64   `xml:"floor"`
-	Room        *int64  `xml:"room"`
-	Plan        string  `xml:"plan"`
-	Balcony     string  `xml:"balcony"`
-	Renovation  string  `xml:"renovation"`
-	Price       float32 `xml:"price"`
-	Area        float32 `xml:"area"`
-	LivingArea  float32 `xml:"living_area"`
-	KitchenArea float32 `xml:"kitchen_area"`
+	FlatID      string           `xml:"flat_id"`
+	Apartment   string           `xml:"apartment"`
+	Floor       int64            `xml:"floor"`
+	Room        validation.Int   `xml:"room"`
+	Plan        string           `xml:"plan"`
+	Balcony     string           `xml:"balcony"`
+	Renovation  string           `xml:"renovation"`
+	Price       validation.Float `xml:"price"`
+	Area        validation.Float `xml:"area"`
+	LivingArea  validation.Float `xml:"living_area"`
+	KitchenArea validation.Float `xml:"kitchen_area"`
 	RoomsArea   struct {
-		Area []string `xml:"area"`
+		Area []validation.Float `xml:"area"`
 	} `xml:"rooms_area"`
 	Bathroom     string `xml:"bathroom"`
 	HousingType  string `xml:"housing_type"`
@@ -121,176 +148,462 @@ type Flat struct {
 	ReadyHousing string `xml:"ready_housing"`
 }
 
-func NewFeed(client *http.Client, url string) *Feed {
+func NewFeed(client *http.Client, url string, opts ...option.Option) *Feed {
+	options := option.Apply(opts...)
+
 	return &Feed{
-		client: client,
-		url:    url,
+		transport:  transport.New(client, options.Transport...),
+		logger:     options.Logger.With(slog.String("platform", platformName), slog.String("url", url)),
+		url:        url,
+		rules:      options.Rules,
+		DecodeMode: options.DecodeMode,
+		MaxAge:     options.MaxAge,
 	}
 }
 
 func (f *Feed) Get(ctx context.Context) error {
-	resp, err := transport.GetResponse(ctx, f.client, f.url)
+	resp, err := f.transport.Get(ctx, f.url)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	AttributeLastModified := resp.Header.Get(transport.HeaderLastModified)
-	if AttributeLastModified != "" {
-		lastModifiedDate, err := time.Parse(time.RFC1123, resp.Header.Get("Last-Modified"))
-		if err != nil {
-			return err
-		}
+	f.Freshness.Header, err = freshness.ParseHeader(resp.Header)
+	if err != nil {
+		return err
+	}
+
+	if f.Freshness.Header.IsZero() {
+		f.logger.DebugContext(ctx, "header not contains `Last-Modified`")
+	}
+
+	responseBody, err := f.transport.ReadBody(resp)
+	if err != nil {
+		return err
+	}
+
+	f.header = resp.Header
+
+	return f.load(responseBody)
+}
 
-		f.LastModified = lastModifiedDate
-	} else {
-		log.Println("Header not contains `Last-Modified`")
+func (f *Feed) ReadFile(name string) error {
+	body, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("can't read feed file. Error:%w", err)
 	}
 
-	responseBody, err := io.ReadAll(resp.Body)
+	f.Freshness.FileModTime, err = freshness.FileModTime(name)
 	if err != nil {
 		return err
 	}
 
-	err = xml.Unmarshal(responseBody, &f.Data)
+	return f.load(body)
+}
+
+// Restore decodes body as if it was fetched with the response header, e.g. from an archived snapshot.
+func (f *Feed) Restore(body []byte, header http.Header) error {
+	var err error
+
+	f.Freshness.Header, err = freshness.ParseHeader(header)
+	if err != nil {
+		return err
+	}
+
+	f.header = header
+
+	return f.load(body)
+}
+
+func (f *Feed) Read(r io.Reader) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("can't read feed. Error:%w", err)
+	}
+
+	return f.load(body)
+}
+
+func (f *Feed) load(body []byte) error {
+	var err error
+
+	f.Data = Data{}
+	f.raw = body
+
+	f.decoded, err = decoding.Decode(body, &f.Data, f.DecodeMode, complexElement, buildingElement, flatElement)
 	if err != nil {
 		return err
 	}
 
 	f.isGet = true
+	f.LastModified, _ = f.Freshness.Resolve()
+
+	f.logger.Debug("feed decoded", slog.Int("bytes", len(body)), slog.Int("lots", f.Lots()),
+		slog.Int("decode_findings", len(f.decoded.Findings)), slog.Time("last_modified", f.LastModified))
 
-	//TODO Исправить значение f.LastModified
 	return nil
 }
 
+func (f *Feed) IsStale(now time.Time) bool {
+	return f.Freshness.IsStale(now, f.MaxAge)
+}
+
+func (f *Feed) CheckFreshness(now time.Time) []validation.Finding {
+	return f.rules.Filter(f.Freshness.Check(now, f.MaxAge))
+}
+
+func (f *Feed) Raw() []byte {
+	return f.raw
+}
+
+// Header returns the response header of the last download.
+func (f *Feed) Header() http.Header {
+	return f.header
+}
+
+func (f *Feed) ContentHash() string {
+	return freshness.Hash(f.raw)
+}
+
+func (f *Feed) Lots() int {
+	return len(f.Data.Flats())
+}
+
+// lotIDs returns the lot IDs in document order.
+func (f *Feed) lotIDs() []string {
+	flats := f.Data.Flats()
+
+	ids := make([]string, 0, len(flats))
+	for _, lot := range flats {
+		ids = append(ids, lot.FlatID)
+	}
+
+	return ids
+}
+
+func (f *Feed) Summaries() []summary.Lot {
+	lots := make([]summary.Lot, 0)
+
+	f.Data.EachFlat(func(residence *Complex, building *Building, flat *Flat) {
+		images := make([]string, 0, 2)
+		for _, image := range []string{flat.Plan, building.Image} {
+			if image != "" {
+				images = append(images, image)
+			}
+		}
+
+		lots = append(lots, summary.Lot{
+			ID:       flat.FlatID,
+			Title:    summary.Title(residence.Name, building.Name, flat.Apartment),
+			Images:   images,
+			Position: f.decoded.Positions.At(flatElement, len(lots)),
+		})
+	})
+
+	return lots
+}
+
+func (f *Feed) Stats() summary.Stats {
+	return summary.Compute(f.offers())
+}
+
+// BuildingStats returns the statistics of every building, keyed by complex and building name.
+func (f *Feed) BuildingStats() map[string]summary.Stats {
+	buildings := make(map[string][]summary.Offer)
+
+	for _, offer := range f.offers() {
+		buildings[offer.Building] = append(buildings[offer.Building], offer)
+	}
+
+	stats := make(map[string]summary.Stats, len(buildings))
+	for name, offers := range buildings {
+		stats[name] = summary.Compute(offers)
+	}
+
+	return stats
+}
+
+func (f *Feed) offers() []summary.Offer {
+	offers := make([]summary.Offer, 0)
+
+	f.Data.EachFlat(func(residence *Complex, building *Building, flat *Flat) {
+		offer := summary.Offer{
+			Building:   summary.Title(residence.Name, building.Name),
+			Decoration: flat.Renovation,
+			Price:      flat.Price.Value,
+			Area:       flat.Area.Value,
+			Photos:     len(residence.Images.Image),
+			HasPlan:    flat.Plan != "",
+		}
+
+		switch rooms := flatRooms(flat.Room); rooms.Kind {
+		case validation.RoomsStudio:
+			offer.Rooms = summary.RoomsStudio
+		case validation.RoomsCount:
+			offer.Rooms = summary.Rooms(rooms.Count)
+		}
+
+		if offer.Decoration == "" && flat.Decoration != 0 {
+			offer.Decoration = strconv.FormatInt(flat.Decoration, 10)
+		}
+
+		if building.Image != "" {
+			offer.Photos++
+		}
+
+		offers = append(offers, offer)
+	})
+
+	return offers
+}
+
 func (f *Feed) GetInfo(ctx context.Context) error {
-	resp, err := transport.GetOnlyHeader(ctx, f.client, f.url)
+	resp, err := f.transport.Head(ctx, f.url)
 	if err != nil {
 		return err
 	}
 
 	defer resp.Body.Close()
 
-	attributeLastModified := resp.Header.Get(transport.HeaderLastModified)
-	if attributeLastModified != "" {
-		lastModifiedDate, err := time.Parse(time.RFC1123, resp.Header.Get("Last-Modified"))
-		if err != nil {
-			return err
-		}
+	lastModified, err := freshness.ParseHeader(resp.Header)
+	if err != nil {
+		return err
+	}
 
-		f.LastModified = lastModifiedDate
-	} else {
-		log.Println("Header not contains `Last-Modified`")
+	if lastModified.IsZero() {
+		f.logger.DebugContext(ctx, "header not contains `Last-Modified`")
 	}
 
+	f.Freshness.Header = lastModified
+	f.LastModified, _ = f.Freshness.Resolve()
+
 	return nil
 }
 
+func (d *Data) Buildings() []Building {
+	buildings := make([]Building, 0)
+	for _, residence := range d.Complex {
+		buildings = append(buildings, residence.Buildings.Building...)
+	}
+
+	return buildings
+}
+
+func (d *Data) Flats() []Flat {
+	flats := make([]Flat, 0)
+	for _, residence := range d.Complex {
+		for _, building := range residence.Buildings.Building {
+			flats = append(flats, building.Flats.Flat...)
+		}
+	}
+
+	return flats
+}
+
+func (d *Data) EachFlat(fn func(residence *Complex, building *Building, flat *Flat)) {
+	for i := range d.Complex {
+		residence := &d.Complex[i]
+		for j := range residence.Buildings.Building {
+			building := &residence.Buildings.Building[j]
+			for k := range building.Flats.Flat {
+				fn(residence, building, &building.Flats.Flat[k])
+			}
+		}
+	}
+}
+
 func (f *Feed) Check() ([]string, error) {
+	findings, err := f.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return validation.Strings(findings), nil
+}
+
+func (f *Feed) Validate() ([]validation.Finding, error) {
 	if !f.isGet {
-		return nil, errors.New("feed not got")
+		return nil, &transport.NotFetchedError{URL: f.url}
 	}
 
-	results := make([]string, 0)
-	residence := &f.Data.Complex
-	if len(residence.Buildings.Building) < 2 {
-		results = append(results, validation.MsgEmptyFeed)
+	results := make([]validation.Finding, 0, len(f.decoded.Findings))
+	results = append(results, f.decoded.LotFindings(flatElement, f.lotIDs())...)
+	if len(f.Data.Buildings()) < 2 {
+		validation.Add(&results, validation.RuleFeedSize, "", "complexes", validation.MsgEmptyFeed)
+
+		return f.rules.Filter(results), nil
+	}
+
+	cursor := &lotCursor{}
+	for idx := range f.Data.Complex {
+		start := len(results)
+		f.checkComplex(idx, &f.Data.Complex[idx], cursor, &results)
+		validation.SetPosition(results[start:], f.decoded.Positions.At(complexElement, idx))
+	}
+
+	return f.rules.Filter(results), nil
+}
+
+// lotCursor counts buildings and flats across complexes in document order to look up their positions.
+type lotCursor struct {
+	building int
+	flat     int
+}
 
-		return results, nil
+func (f *Feed) checkComplex(idx int, residence *Complex, cursor *lotCursor, results *[]validation.Finding) {
+	complexPath := fmt.Sprintf("Complex[%d]", idx)
+	if residence.ID != "" {
+		complexPath = fmt.Sprintf("Complex[id=%s]", residence.ID)
 	}
 
-	path := "Complex"
+	path := complexPath
 
-	validation.CheckString(path, "ID", residence.ID, &results)
-	validation.CheckString(path, "Name", residence.Name, &results)
-	validation.CheckString(path, "Address", residence.Address, &results)
-	validation.CheckString(path, "Latitude", residence.Latitude, &results)
-	validation.CheckString(path, "Longitude", residence.Longitude, &results)
+	validation.CheckFilled(path, "ID", residence.ID, results)
+	validation.CheckFilled(path, "Name", residence.Name, results)
+	validation.CheckFilled(path, "Address", residence.Address, results)
+	validation.CheckFilled(path, "Latitude", residence.Latitude, results)
+	validation.CheckFilled(path, "Longitude", residence.Longitude, results)
 
 	for idx, image := range residence.Images.Image {
-		validation.CheckStringWithPos(idx, "Complex.Images.Image", "Image", image, &results)
+		validation.CheckFilledWithPos(idx, complexPath+".Images.Image", "Image", image, results)
 	}
 
-	path = "Complex.DescriptionMain"
+	path = complexPath + ".DescriptionMain"
 	descriptionMain := &residence.DescriptionMain
-	validation.CheckString(path, "Title", descriptionMain.Title, &results)
-	validation.CheckString(path, "Text", descriptionMain.Text, &results)
+	validation.CheckFilled(path, "Title", descriptionMain.Title, results)
+	validation.CheckFilled(path, "Text", descriptionMain.Text, results)
 
-	path = "Complex.ProfitsMain.ProfitMain"
+	path = complexPath + ".ProfitsMain.ProfitMain"
 
 	profits := residence.ProfitsMain.ProfitMain
 	for idx, profit := range profits {
-		validation.CheckStringWithPos(idx, path, "Title", profit.Title, &results)
-		validation.CheckStringWithPos(idx, path, "Text", profit.Text, &results)
-		validation.CheckStringWithPos(idx, path, "Image", profit.Image, &results)
+		validation.CheckFilledWithPos(idx, path, "Title", profit.Title, results)
+		validation.CheckFilledWithPos(idx, path, "Text", profit.Text, results)
+		validation.CheckFilledWithPos(idx, path, "Image", profit.Image, results)
 	}
 
-	path = "Complex.Buildings.Building"
+	path = complexPath + ".Buildings.Building"
 
-	buildings := f.Data.Complex.Buildings.Building
+	buildings := residence.Buildings.Building
 	for pos, building := range buildings {
-		validation.CheckStringWithPos(pos, path, "ID", building.ID, &results)
-		validation.CheckStringWithID(building.ID, path, "Fz214", building.Fz214, &results)
-		validation.CheckStringWithID(building.ID, path, "Name", building.Name, &results)
-		validation.CheckZeroWithID(building.ID, path, "Floors", int(building.Floors), &results)
-		validation.CheckStringWithID(building.ID, path, "BuildingState", building.BuildingState, &results)
-		validation.CheckZeroWithID(building.ID, path, "BuiltYear", int(building.BuiltYear), &results)
-		validation.CheckZeroWithID(building.ID, path, "ReadyQuarter", int(building.ReadyQuarter), &results)
-		validation.CheckStringWithID(building.ID, path, "BuildingType", building.BuildingType, &results)
+		start := len(*results)
+
+		validation.CheckFilledWithPos(pos, path, "ID", building.ID, results)
+		validation.CheckFilledWithID(building.ID, path, "Fz214", building.Fz214, results)
+		validation.CheckFilledWithID(building.ID, path, "Name", building.Name, results)
+		validation.CheckNumberWithID(building.ID, path, "Floors", int(building.Floors), results)
+		validation.CheckFilledWithID(building.ID, path, "BuildingState", building.BuildingState, results)
+		validation.CheckNumberWithID(building.ID, path, "BuiltYear", int(building.BuiltYear), results)
+		validation.CheckNumberWithID(building.ID, path, "ReadyQuarter", int(building.ReadyQuarter), results)
+		validation.CheckFilledWithID(building.ID, path, "BuildingType", building.BuildingType, results)
 
 		if building.BuiltYear < int64(time.Now().Year()) && building.BuildingState == "unfinished" {
-			results = append(results, fmt.Sprintf("BuildingState == unfinished for %v. InternalID: %v", building.BuiltYear, building.ID))
+			validation.Add(results, validation.RuleDeadline, building.ID, path+".BuildingState", fmt.Sprintf("%s: BuildingState == unfinished for %v", complexPath, building.BuiltYear))
 		}
 
-		f.checkLots(building.Flats.Flat, int(building.Floors), &results)
+		f.checkLots(complexPath, building.Flats.Flat, int(building.Floors), cursor, results)
+		validation.SetPosition((*results)[start:], f.decoded.Positions.At(buildingElement, cursor.building))
+		cursor.building++
 	}
 
-	path = "Complex.SalesInfo"
-	salesInfo := &f.Data.Complex.SalesInfo
-	validation.CheckString(path, "SalesPhone", salesInfo.SalesPhone, &results)
-	validation.CheckString(path, "SalesAddress", salesInfo.SalesAddress, &results)
-	validation.CheckString(path, "SalesLatitude", salesInfo.SalesLatitude, &results)
-	validation.CheckString(path, "SalesLongitude", salesInfo.SalesLongitude, &results)
-
-	path = "Complex.Developer"
-	developer := &f.Data.Complex.Developer
-	validation.CheckString(path, "Name", developer.Name, &results)
-	validation.CheckString(path, "Phone", developer.Phone, &results)
-	validation.CheckString(path, "Site", developer.Site, &results)
-	validation.CheckString(path, "Logo", developer.Logo, &results)
-
-	return results, nil
+	path = complexPath + ".SalesInfo"
+	salesInfo := &residence.SalesInfo
+	validation.CheckFilled(path, "SalesPhone", salesInfo.SalesPhone, results)
+	validation.CheckFilled(path, "SalesAddress", salesInfo.SalesAddress, results)
+	validation.CheckFilled(path, "SalesLatitude", salesInfo.SalesLatitude, results)
+	validation.CheckFilled(path, "SalesLongitude", salesInfo.SalesLongitude, results)
+
+	path = complexPath + ".Developer"
+	developer := &residence.Developer
+	validation.CheckFilled(path, "Name", developer.Name, results)
+	validation.CheckFilled(path, "Phone", developer.Phone, results)
+	validation.CheckFilled(path, "Site", developer.Site, results)
+	validation.CheckFilled(path, "Logo", developer.Logo, results)
 }
 
-func (f *Feed) checkLots(flats []Flat, floors int, results *[]string) {
-	path := "Flats.Flat"
+func (f *Feed) checkLots(complexPath string, flats []Flat, floors int, cursor *lotCursor, results *[]validation.Finding) {
+	path := complexPath + ".Flats.Flat"
 	for idx, lot := range flats {
-		validation.CheckStringWithPos(idx, path, "FlatID", lot.FlatID, results)
-		validation.CheckZeroWithID(lot.FlatID, path, "Floor", int(lot.Floor), results)
+		start := len(*results)
 
-		if lot.Room == nil {
-			*results = append(*results, fmt.Sprintf("Field Flats.Room is empty. InternalID: %v", lot.FlatID))
-		}
+		validation.CheckFilledWithPos(idx, path, "FlatID", lot.FlatID, results)
+		validation.CheckNumberWithID(lot.FlatID, path, "Floor", int(lot.Floor), results)
+
+		validation.CheckSetWithID(lot.FlatID, path, "Room", lot.Room, results)
 
-		validation.CheckStringWithID(lot.FlatID, path, "Plan", lot.Plan, results)
-		validation.CheckStringWithID(lot.FlatID, path, "Balcony", lot.Balcony, results)
-		validation.CheckZeroWithID(lot.FlatID, path, "Price", lot.Price, results)
-		validation.CheckZeroWithID(lot.FlatID, path, "Area", lot.Area, results)
+		validation.CheckFilledWithID(lot.FlatID, path, "Plan", lot.Plan, results)
+		validation.CheckFilledWithID(lot.FlatID, path, "Balcony", lot.Balcony, results)
+		validation.CheckNonZeroWithID(lot.FlatID, path, "Price", lot.Price, results)
+		validation.CheckNonZeroWithID(lot.FlatID, path, "Area", lot.Area, results)
 
-		isOk := validation.CheckZeroWithID(lot.FlatID, path, "LivingArea", lot.LivingArea, results)
-		if !isOk {
-			for i, room := range lot.RoomsArea.Area {
-				if room == "" {
-					*results = append(*results, fmt.Sprintf("Field Flats.Flat.RoomsArea.Area[%v] is empty. InternalID: %v", i, lot.FlatID))
+		rooms := flatRooms(lot.Room)
+
+		if rooms.HasLiving() {
+			isOk := validation.CheckNonZeroWithID(lot.FlatID, path, "LivingArea", lot.LivingArea, results)
+			if !isOk {
+				for i, room := range lot.RoomsArea.Area {
+					if room.State == validation.StateEmpty {
+						validation.Add(results, validation.RuleEmpty, lot.FlatID, path+".RoomsArea.Area", fmt.Sprintf("Field %s.RoomsArea.Area[%v] is empty", path, i))
+					}
 				}
 			}
 		}
 
-		validation.CheckZeroWithID(lot.FlatID, path, "KitchenArea", lot.KitchenArea, results)
-		validation.CheckStringWithID(lot.FlatID, path, "Bathroom", lot.Bathroom, results)
+		validation.CheckNonZeroWithID(lot.FlatID, path, "KitchenArea", lot.KitchenArea, results)
+		validation.CheckAreaSum(lot.FlatID, path, lot.Area.Value, lot.LivingArea.Value, lot.KitchenArea.Value, results)
+
+		roomsArea := make([]float64, 0, len(lot.RoomsArea.Area))
+		for i, room := range lot.RoomsArea.Area {
+			switch room.State {
+			case validation.StateSet:
+				roomsArea = append(roomsArea, room.Value)
+			case validation.StateInvalid:
+				validation.Add(results, validation.RuleFormat, lot.FlatID, path+".RoomsArea.Area", fmt.Sprintf("Field %s.RoomsArea.Area[%v] is not a number", path, i))
+			}
+		}
+
+		validation.CheckRoomsAreaSum(lot.FlatID, path, lot.LivingArea.Value, roomsArea, results)
+
+		validation.CheckRooms(lot.FlatID, path, validation.Layout{
+			Field:          "Room",
+			Rooms:          rooms,
+			RoomAreas:      roomsArea,
+			RoomAreasField: "RoomsArea.Area",
+			Total:          lot.Area.Value,
+		}, results)
+
+		validation.CheckFilledWithID(lot.FlatID, path, "Bathroom", lot.Bathroom, results)
 
 		if lot.Floor > int64(floors) {
-			*results = append(*results, fmt.Sprintf("Field Flats.Flat.Floor is bigger than building.Floors. InternalID: %v", lot.FlatID))
+			validation.Add(results, validation.RuleConsistency, lot.FlatID, path+".Floor", fmt.Sprintf("Field %s.Floor is bigger than building.Floors", path))
 		}
+
+		validation.SetPosition((*results)[start:], f.decoded.Positions.At(flatElement, cursor.flat))
+		cursor.flat++
+	}
+}
+
+func (f *Feed) CheckStructure() ([]validation.Finding, error) {
+	if !f.isGet {
+		return nil, &transport.NotFetchedError{URL: f.url}
+	}
+
+	findings, err := schema.Validate(bytes.NewReader(f.raw), Schema())
+	if err != nil {
+		return nil, err
+	}
+
+	return f.rules.Filter(findings), nil
+}
+
+// flatRooms returns the rooms of the flat, zero rooms is a studio.
+func flatRooms(room validation.Int) validation.Rooms {
+	switch {
+	case !room.IsSet():
+		return validation.Rooms{}
+	case room.Value == 0:
+		return validation.Rooms{Kind: validation.RoomsStudio, Raw: "0"}
+	default:
+		return validation.RoomsFromCount(room.Value)
 	}
 }
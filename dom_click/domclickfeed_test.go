@@ -0,0 +1,116 @@
+package domclick
+
+import (
+	"encoding/xml"
+	"github.com/zfullio/price-placements/v2/validation"
+	"strings"
+	"testing"
+)
+
+func TestRoomsAreaNumbers(t *testing.T) {
+	var flat Flat
+
+	data := `<flat><rooms_area><area>10,5</area><area>1 234,5</area><area>1.234,5</area><area></area><area>x</area></rooms_area></flat>`
+	if err := xml.Unmarshal([]byte(data), &flat); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []validation.Float{
+		{Value: 10.5, State: validation.StateSet},
+		{Value: 1234.5, State: validation.StateSet},
+		{Value: 1234.5, State: validation.StateSet},
+		{State: validation.StateEmpty},
+		{State: validation.StateInvalid},
+	}
+
+	if len(flat.RoomsArea.Area) != len(want) {
+		t.Fatalf("got %v areas, want %v", len(flat.RoomsArea.Area), len(want))
+	}
+
+	for idx, area := range flat.RoomsArea.Area {
+		if area.Value != want[idx].Value || area.State != want[idx].State {
+			t.Errorf("area %d is %+v, want %+v", idx, area, want[idx])
+		}
+	}
+}
+
+const complexesFeed = `<complexes>
+<complex>
+	<id>A</id>
+	<buildings>
+		<building><id>b1</id><flats><flat><flat_id>f1</flat_id></flat><flat><flat_id>f2</flat_id></flat></flats></building>
+	</buildings>
+</complex>
+<complex>
+	<buildings>
+		<building><id>b2</id><flats><flat><flat_id>f3</flat_id></flat></flats></building>
+	</buildings>
+</complex>
+</complexes>`
+
+func TestValidateEveryComplex(t *testing.T) {
+	feed := NewFeed(nil, "")
+	if err := feed.Read(strings.NewReader(complexesFeed)); err != nil {
+		t.Fatal(err)
+	}
+
+	findings, err := feed.Validate()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		id   string
+		path string
+		line int
+	}{
+		{id: "", path: "Complex[id=A].Name", line: 2},
+		{id: "b1", path: "Complex[id=A].Buildings.Building.Name", line: 5},
+		{id: "f2", path: "Complex[id=A].Flats.Flat.Plan", line: 5},
+		{id: "", path: "Complex[1].ID", line: 8},
+		{id: "b2", path: "Complex[1].Buildings.Building.Name", line: 10},
+		{id: "f3", path: "Complex[1].Flats.Flat.Plan", line: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path+"/"+tt.id, func(t *testing.T) {
+			for _, finding := range findings {
+				if finding.Path == tt.path && finding.ID == tt.id {
+					if finding.Position.Line != tt.line {
+						t.Errorf("finding is on line %v, want %v", finding.Position.Line, tt.line)
+					}
+
+					return
+				}
+			}
+
+			t.Errorf("no finding for %s %s in %v", tt.path, tt.id, findings)
+		})
+	}
+}
+
+func TestEachFlat(t *testing.T) {
+	feed := NewFeed(nil, "")
+	if err := feed.Read(strings.NewReader(complexesFeed)); err != nil {
+		t.Fatal(err)
+	}
+
+	var visited []string
+
+	feed.Data.EachFlat(func(residence *Complex, building *Building, flat *Flat) {
+		visited = append(visited, residence.ID+"/"+building.ID+"/"+flat.FlatID)
+	})
+
+	want := []string{"A/b1/f1", "A/b1/f2", "/b2/f3"}
+	if strings.Join(visited, ",") != strings.Join(want, ",") {
+		t.Errorf("visited %v, want %v", visited, want)
+	}
+
+	if ids := feed.lotIDs(); strings.Join(ids, ",") != "f1,f2,f3" {
+		t.Errorf("lot IDs are %v, want [f1 f2 f3]", ids)
+	}
+
+	if feed.Lots() != 3 {
+		t.Errorf("got %v lots, want 3", feed.Lots())
+	}
+}
@@ -0,0 +1,142 @@
+package freshness
+
+import (
+	"fmt"
+	"github.com/zfullio/price-placements/v2/transport"
+	"github.com/zfullio/price-placements/v2/validation"
+	"net/http"
+	"os"
+	"time"
+)
+
+const DefaultMaxAge = 24 * time.Hour
+
+type Source string
+
+const (
+	SourceNone           Source = ""
+	SourceGenerationDate Source = "generation-date"
+	SourceHeader         Source = "header"
+	SourceOfferUpdate    Source = "offer-update"
+	SourceFileModTime    Source = "file-mtime"
+)
+
+// Freshness collects every date a feed reports about its modification.
+type Freshness struct {
+	// Header is the Last-Modified header of the feed response.
+	Header time.Time
+	// Generated is the generation date written into the feed itself.
+	Generated time.Time
+	// LatestOffer is the most recent last update date of the feed offers.
+	LatestOffer time.Time
+	// FileModTime is the modification time of a feed read from disk.
+	FileModTime time.Time
+}
+
+// Resolve returns the most reliable modification date: the date the feed states about itself wins
+// over the header, which wins over offer dates and the file modification time.
+func (f Freshness) Resolve() (time.Time, Source) {
+	for _, candidate := range []struct {
+		date   time.Time
+		source Source
+	}{
+		{f.Generated, SourceGenerationDate},
+		{f.Header, SourceHeader},
+		{f.LatestOffer, SourceOfferUpdate},
+		{f.FileModTime, SourceFileModTime},
+	} {
+		if !candidate.date.IsZero() {
+			return candidate.date, candidate.source
+		}
+	}
+
+	return time.Time{}, SourceNone
+}
+
+func (f Freshness) Age(now time.Time) (time.Duration, bool) {
+	date, source := f.Resolve()
+	if source == SourceNone {
+		return 0, false
+	}
+
+	return now.Sub(date), true
+}
+
+// IsStale reports whether the feed is older than maxAge. A feed without any known date is stale.
+func (f Freshness) IsStale(now time.Time, maxAge time.Duration) bool {
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+
+	age, ok := f.Age(now)
+
+	return !ok || age > maxAge
+}
+
+func (f Freshness) Check(now time.Time, maxAge time.Duration) []validation.Finding {
+	findings := make([]validation.Finding, 0)
+
+	if maxAge <= 0 {
+		maxAge = DefaultMaxAge
+	}
+
+	age, ok := f.Age(now)
+	if !ok {
+		validation.Add(&findings, validation.RuleFreshnessUnknown, "", "", "feed modification date is unknown")
+
+		return findings
+	}
+
+	if age > maxAge {
+		date, source := f.Resolve()
+		validation.Add(&findings, validation.RuleStale, "", string(source),
+			fmt.Sprintf("feed was last modified at %s (%s), older than %s", date.Format(time.RFC3339), source, maxAge))
+	}
+
+	return findings
+}
+
+// ParseHeader returns the Last-Modified date of the response, the zero time if the header is absent.
+func ParseHeader(header http.Header) (time.Time, error) {
+	value := header.Get(transport.HeaderLastModified)
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("can't parse header %s. Error:%w", transport.HeaderLastModified, err)
+	}
+
+	return date, nil
+}
+
+func ParseDate(value string) time.Time {
+	date, ok := validation.ParseDate(value)
+	if !ok {
+		return time.Time{}
+	}
+
+	return date
+}
+
+func FileModTime(name string) (time.Time, error) {
+	info, err := os.Stat(name)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("can't get file info. Error:%w", err)
+	}
+
+	return info.ModTime(), nil
+}
+
+func Latest(dates ...time.Time) time.Time {
+	var latest time.Time
+
+	for _, date := range dates {
+		if date.After(latest) {
+			latest = date
+		}
+	}
+
+	return latest
+}
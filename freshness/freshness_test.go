@@ -0,0 +1,39 @@
+package freshness
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolve(t *testing.T) {
+	generated := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	header := generated.Add(time.Hour)
+	offer := generated.Add(2 * time.Hour)
+	file := generated.Add(3 * time.Hour)
+
+	tests := []struct {
+		name      string
+		freshness Freshness
+		date      time.Time
+		source    Source
+	}{
+		{name: "generated", freshness: Freshness{Generated: generated, Header: header, LatestOffer: offer, FileModTime: file}, date: generated, source: SourceGenerationDate},
+		{name: "header", freshness: Freshness{Header: header, LatestOffer: offer, FileModTime: file}, date: header, source: SourceHeader},
+		{name: "latest offer", freshness: Freshness{LatestOffer: offer, FileModTime: file}, date: offer, source: SourceOfferUpdate},
+		{name: "file", freshness: Freshness{FileModTime: file}, date: file, source: SourceFileModTime},
+		{name: "none", source: SourceNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			date, source := tt.freshness.Resolve()
+			if !date.Equal(tt.date) || source != tt.source {
+				t.Errorf("got %v from %q, want %v from %q", date, source, tt.date, tt.source)
+			}
+
+			if _, ok := tt.freshness.Age(file); ok != (tt.source != SourceNone) {
+				t.Errorf("age is known: %v", ok)
+			}
+		})
+	}
+}
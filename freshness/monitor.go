@@ -0,0 +1,101 @@
+package freshness
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"github.com/zfullio/price-placements/v2/validation"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultUnchangedFor = 48 * time.Hour
+	DefaultOfferMaxAge  = 30 * 24 * time.Hour
+
+	// clockSkew is the tolerance for feed generation dates set by a server with a slightly fast clock.
+	clockSkew = 5 * time.Minute
+)
+
+type Observation struct {
+	Fetched      time.Time
+	LastModified time.Time
+	Hash         string
+}
+
+type State struct {
+	Last Observation
+	// Changed is the fetch time the content hash was last seen to change.
+	Changed time.Time
+}
+
+// Monitor tracks modification dates and content hashes of feeds across fetches.
+type Monitor struct {
+	mu           sync.Mutex
+	states       map[string]State
+	UnchangedFor time.Duration
+}
+
+func NewMonitor(unchangedFor time.Duration) *Monitor {
+	if unchangedFor <= 0 {
+		unchangedFor = DefaultUnchangedFor
+	}
+
+	return &Monitor{
+		states:       make(map[string]State),
+		UnchangedFor: unchangedFor,
+	}
+}
+
+// Observe records a fetch of the feed identified by key and returns freshness findings for it.
+func (m *Monitor) Observe(key string, freshness Freshness, hash string, now time.Time) []validation.Finding {
+	findings := make([]validation.Finding, 0)
+
+	lastModified, _ := freshness.Resolve()
+
+	m.mu.Lock()
+	state, ok := m.states[key]
+
+	if !ok || state.Last.Hash != hash || state.Last.LastModified.Before(lastModified) {
+		state.Changed = now
+	}
+
+	state.Last = Observation{Fetched: now, LastModified: lastModified, Hash: hash}
+	m.states[key] = state
+	m.mu.Unlock()
+
+	if unchanged := now.Sub(state.Changed); unchanged > m.UnchangedFor {
+		validation.Add(&findings, validation.RuleUnchanged, "", key,
+			fmt.Sprintf("feed %s has not changed since %s (%s)", key, state.Changed.Format(time.RFC3339), unchanged.Round(time.Minute)))
+	}
+
+	if freshness.Generated.After(now.Add(clockSkew)) {
+		validation.Add(&findings, validation.RuleFutureDate, "", key,
+			fmt.Sprintf("feed %s generation date %s is in the future", key, freshness.Generated.Format(time.RFC3339)))
+	}
+
+	return findings
+}
+
+func (m *Monitor) State(key string) (State, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state, ok := m.states[key]
+
+	return state, ok
+}
+
+// Restore sets a previously saved state, e.g. after a restart.
+func (m *Monitor) Restore(key string, state State) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.states[key] = state
+}
+
+func Hash(body []byte) string {
+	sum := sha256.Sum256(body)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,121 @@
+package freshness
+
+import (
+	"github.com/zfullio/price-placements/v2/validation"
+	"testing"
+	"time"
+)
+
+func TestMonitorObserve(t *testing.T) {
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	dated := Freshness{Generated: start}
+
+	type observation struct {
+		freshness Freshness
+		hash      string
+		after     time.Duration
+	}
+
+	tests := []struct {
+		name         string
+		observations []observation
+		rules        []string
+		changed      time.Duration
+	}{
+		{
+			name:         "first observation",
+			observations: []observation{{freshness: dated, hash: "a"}},
+		},
+		{
+			name:         "equal hash within the period",
+			observations: []observation{{freshness: dated, hash: "a"}, {freshness: dated, hash: "a", after: time.Hour}},
+		},
+		{
+			name:         "equal hash",
+			observations: []observation{{freshness: dated, hash: "a"}, {freshness: dated, hash: "a", after: 49 * time.Hour}},
+			rules:        []string{validation.RuleUnchanged},
+		},
+		{
+			name:         "changed hash",
+			observations: []observation{{freshness: dated, hash: "a"}, {freshness: dated, hash: "b", after: 49 * time.Hour}},
+			changed:      49 * time.Hour,
+		},
+		{
+			name: "newer date",
+			observations: []observation{
+				{freshness: dated, hash: "a"},
+				{freshness: Freshness{Generated: start.Add(time.Hour)}, hash: "a", after: 49 * time.Hour},
+			},
+			changed: 49 * time.Hour,
+		},
+		{
+			name:         "clock skew",
+			observations: []observation{{freshness: Freshness{Generated: start.Add(4 * time.Minute)}, hash: "a"}},
+		},
+		{
+			name:         "future date",
+			observations: []observation{{freshness: Freshness{Generated: start.Add(time.Hour)}, hash: "a"}},
+			rules:        []string{validation.RuleFutureDate},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			monitor := NewMonitor(0)
+
+			var findings []validation.Finding
+			for _, obs := range tt.observations {
+				findings = monitor.Observe("feed", obs.freshness, obs.hash, start.Add(obs.after))
+			}
+
+			if len(findings) != len(tt.rules) {
+				t.Fatalf("got findings %v, want rules %v", findings, tt.rules)
+			}
+
+			for idx, finding := range findings {
+				if finding.Rule != tt.rules[idx] || finding.Path != "feed" {
+					t.Errorf("got finding %+v, want rule %s", finding, tt.rules[idx])
+				}
+			}
+
+			state, ok := monitor.State("feed")
+			if !ok || !state.Changed.Equal(start.Add(tt.changed)) {
+				t.Errorf("content changed at %v, want %v", state.Changed, start.Add(tt.changed))
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		freshness Freshness
+		maxAge    time.Duration
+		rule      string
+	}{
+		{name: "fresh", freshness: Freshness{Header: now.Add(-time.Hour)}},
+		{name: "stale", freshness: Freshness{Header: now.Add(-25 * time.Hour)}, rule: validation.RuleStale},
+		{name: "max age", freshness: Freshness{Header: now.Add(-2 * time.Hour)}, maxAge: time.Hour, rule: validation.RuleStale},
+		{name: "unknown", rule: validation.RuleFreshnessUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := tt.freshness.Check(now, tt.maxAge)
+
+			if tt.rule == "" {
+				if len(findings) != 0 {
+					t.Errorf("got findings %v", findings)
+				}
+			} else if len(findings) != 1 || findings[0].Rule != tt.rule {
+				t.Errorf("got findings %v, want rule %s", findings, tt.rule)
+			}
+
+			if stale := tt.freshness.IsStale(now, tt.maxAge); stale != (tt.rule != "") {
+				t.Errorf("stale is %v", stale)
+			}
+		})
+	}
+}
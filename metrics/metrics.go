@@ -0,0 +1,195 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"github.com/zfullio/price-placements/v2/validation"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+type feedKey struct{}
+
+// WithFeed labels the requests made with ctx by the feed name.
+func WithFeed(ctx context.Context, feed string) context.Context {
+	return context.WithValue(ctx, feedKey{}, feed)
+}
+
+func feedName(r *http.Request) string {
+	if feed, ok := r.Context().Value(feedKey{}).(string); ok {
+		return feed
+	}
+
+	return r.URL.Host + r.URL.Path
+}
+
+// Collector gathers feed fetching and validation metrics and serves them in the Prometheus text format.
+type Collector struct {
+	mu           sync.Mutex
+	fetch        *family
+	size         *family
+	status       *family
+	retries      *family
+	lots         *family
+	findings     *family
+	checks       *family
+	lastModified map[string]time.Time
+}
+
+func NewCollector() *Collector {
+	return &Collector{
+		fetch: newHistogram("feed_fetch_duration_seconds", "Duration of feed requests including the body download.",
+			[]float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300}, "feed", "method"),
+		size:         newFamily("feed_response_size_bytes", "Size of the last feed response body.", kindGauge, "feed"),
+		status:       newFamily("feed_http_responses_total", "Feed responses by HTTP status code.", kindCounter, "feed", "code"),
+		retries:      newFamily("feed_fetch_retries_total", "Repeated feed downloads after a failure.", kindCounter, "feed"),
+		lots:         newFamily("feed_lots", "Number of lots in the last checked feed.", kindGauge, "feed", "platform"),
+		findings:     newFamily("feed_findings", "Findings of the last feed check by rule and severity.", kindGauge, "feed", "rule", "severity"),
+		checks:       newFamily("feed_checks_total", "Feed checks by result.", kindCounter, "feed", "result"),
+		lastModified: make(map[string]time.Time),
+	}
+}
+
+// ObserveFetch records a feed request, status 0 means the request failed without a response.
+func (c *Collector) ObserveFetch(feed string, method string, duration time.Duration, status int, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	code := strconv.Itoa(status)
+	if status == 0 {
+		code = "error"
+	}
+
+	c.fetch.observe(duration.Seconds(), feed, method)
+	c.status.add(1, feed, code)
+
+	if method == http.MethodGet {
+		c.size.set(float64(size), feed)
+	}
+}
+
+func (c *Collector) ObserveRetry(feed string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.retries.add(1, feed)
+}
+
+// ObserveCheck records the result of a feed check. err is the error of fetching or validating the feed.
+func (c *Collector) ObserveCheck(feed string, platform string, lots int, findings []validation.Finding, lastModified time.Time, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err != nil {
+		c.checks.add(1, feed, "error")
+
+		return
+	}
+
+	c.checks.add(1, feed, "success")
+	c.lots.set(float64(lots), feed, platform)
+	c.findings.deleteWhere(0, feed)
+
+	for _, finding := range findings {
+		c.findings.add(1, feed, finding.Rule, string(finding.Severity))
+	}
+
+	if !lastModified.IsZero() {
+		c.lastModified[feed] = lastModified
+	}
+}
+
+func (c *Collector) WriteTo(w io.Writer) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var buffer bytes.Buffer
+
+	age := newFamily("feed_age_seconds", "Time since the feed was last modified.", kindGauge, "feed")
+	for feed, lastModified := range c.lastModified {
+		age.set(time.Since(lastModified).Seconds(), feed)
+	}
+
+	for _, f := range []*family{c.fetch, c.size, c.status, c.retries, c.lots, c.findings, c.checks, age} {
+		if err := f.write(&buffer); err != nil {
+			return 0, err
+		}
+	}
+
+	return buffer.WriteTo(w)
+}
+
+func (c *Collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+
+	if _, err := c.WriteTo(w); err != nil {
+		slog.WarnContext(r.Context(), "can't write metrics", slog.Any("error", err))
+	}
+}
+
+// Transport wraps next so that every request is recorded by the collector.
+func (c *Collector) Transport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return roundTripper{collector: c, next: next}
+}
+
+type roundTripper struct {
+	collector *Collector
+	next      http.RoundTripper
+}
+
+func (t roundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	started := time.Now()
+
+	response, err := t.next.RoundTrip(r)
+	if err != nil {
+		t.collector.ObserveFetch(feedName(r), r.Method, time.Since(started), 0, 0)
+
+		return response, err
+	}
+
+	response.Body = &countingBody{
+		ReadCloser: response.Body,
+		done: func(size int64) {
+			t.collector.ObserveFetch(feedName(r), r.Method, time.Since(started), response.StatusCode, size)
+		},
+	}
+
+	return response, nil
+}
+
+func (t roundTripper) Unwrap() http.RoundTripper {
+	return t.next
+}
+
+func (t roundTripper) Wrap(next http.RoundTripper) http.RoundTripper {
+	return roundTripper{collector: t.collector, next: next}
+}
+
+// countingBody reports the number of bytes read once the body is closed.
+type countingBody struct {
+	io.ReadCloser
+	size int64
+	once sync.Once
+	done func(size int64)
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.size += int64(n)
+
+	return n, err
+}
+
+func (b *countingBody) Close() error {
+	b.once.Do(func() { b.done(b.size) })
+
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"bytes"
+	"errors"
+	"github.com/zfullio/price-placements/v2/validation"
+	"net/http"
+	"testing"
+	"time"
+)
+
+const golden = `# HELP feed_fetch_duration_seconds Duration of feed requests including the body download.
+# TYPE feed_fetch_duration_seconds histogram
+feed_fetch_duration_seconds_bucket{feed="shop \"a\\b\"\nx",method="GET",le="0.1"} 0
+feed_fetch_duration_seconds_bucket{feed="shop \"a\\b\"\nx",method="GET",le="0.5"} 1
+feed_fetch_duration_seconds_bucket{feed="shop \"a\\b\"\nx",method="GET",le="1"} 1
+feed_fetch_duration_seconds_bucket{feed="shop \"a\\b\"\nx",method="GET",le="2.5"} 2
+feed_fetch_duration_seconds_bucket{feed="shop \"a\\b\"\nx",method="GET",le="5"} 2
+feed_fetch_duration_seconds_bucket{feed="shop \"a\\b\"\nx",method="GET",le="10"} 2
+feed_fetch_duration_seconds_bucket{feed="shop \"a\\b\"\nx",method="GET",le="30"} 2
+feed_fetch_duration_seconds_bucket{feed="shop \"a\\b\"\nx",method="GET",le="60"} 2
+feed_fetch_duration_seconds_bucket{feed="shop \"a\\b\"\nx",method="GET",le="120"} 2
+feed_fetch_duration_seconds_bucket{feed="shop \"a\\b\"\nx",method="GET",le="300"} 2
+feed_fetch_duration_seconds_bucket{feed="shop \"a\\b\"\nx",method="GET",le="+Inf"} 3
+feed_fetch_duration_seconds_sum{feed="shop \"a\\b\"\nx",method="GET"} 602.25
+feed_fetch_duration_seconds_count{feed="shop \"a\\b\"\nx",method="GET"} 3
+# HELP feed_response_size_bytes Size of the last feed response body.
+# TYPE feed_response_size_bytes gauge
+feed_response_size_bytes{feed="shop \"a\\b\"\nx"} 0
+# HELP feed_http_responses_total Feed responses by HTTP status code.
+# TYPE feed_http_responses_total counter
+feed_http_responses_total{feed="shop \"a\\b\"\nx",code="200"} 2
+feed_http_responses_total{feed="shop \"a\\b\"\nx",code="error"} 1
+# HELP feed_fetch_retries_total Repeated feed downloads after a failure.
+# TYPE feed_fetch_retries_total counter
+feed_fetch_retries_total{feed="other"} 1
+# HELP feed_lots Number of lots in the last checked feed.
+# TYPE feed_lots gauge
+feed_lots{feed="other",platform="cian"} 7
+feed_lots{feed="shop",platform="avito"} 12
+# HELP feed_findings Findings of the last feed check by rule and severity.
+# TYPE feed_findings gauge
+feed_findings{feed="other",rule="empty",severity="error"} 1
+feed_findings{feed="shop",rule="zero",severity="warning"} 2
+# HELP feed_checks_total Feed checks by result.
+# TYPE feed_checks_total counter
+feed_checks_total{feed="other",result="success"} 1
+feed_checks_total{feed="shop",result="error"} 1
+feed_checks_total{feed="shop",result="success"} 2
+`
+
+func TestCollectorWrite(t *testing.T) {
+	collector := NewCollector()
+
+	feed := "shop \"a\\b\"\nx"
+	collector.ObserveFetch(feed, http.MethodGet, 250*time.Millisecond, 200, 1024)
+	collector.ObserveFetch(feed, http.MethodGet, 2*time.Second, 200, 2048)
+	collector.ObserveFetch(feed, http.MethodGet, 600*time.Second, 0, 0)
+	collector.ObserveRetry("other")
+
+	empty := validation.Finding{Rule: validation.RuleEmpty, Severity: validation.SeverityError}
+	zero := validation.Finding{Rule: validation.RuleZero, Severity: validation.SeverityWarning}
+
+	// The second check of shop replaces the findings of the first one, other keeps its findings.
+	collector.ObserveCheck("shop", "avito", 10, []validation.Finding{empty, empty}, time.Time{}, nil)
+	collector.ObserveCheck("other", "cian", 7, []validation.Finding{empty}, time.Time{}, nil)
+	collector.ObserveCheck("shop", "avito", 12, []validation.Finding{zero, zero}, time.Time{}, nil)
+	collector.ObserveCheck("shop", "avito", 0, nil, time.Time{}, errors.New("feed not available"))
+
+	var buffer bytes.Buffer
+	if _, err := collector.WriteTo(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	if buffer.String() != golden {
+		t.Errorf("got\n%s\nwant\n%s", buffer.String(), golden)
+	}
+}
+
+func TestWriteEmpty(t *testing.T) {
+	var buffer bytes.Buffer
+	if _, err := NewCollector().WriteTo(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	if buffer.Len() != 0 {
+		t.Errorf("got %q for no observations", buffer.String())
+	}
+}
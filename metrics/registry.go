@@ -0,0 +1,162 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	kindCounter   = "counter"
+	kindGauge     = "gauge"
+	kindHistogram = "histogram"
+)
+
+type series struct {
+	labels  []string
+	value   float64
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// family is a metric with all its label combinations, written in the Prometheus text format.
+type family struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+func newFamily(name string, help string, kind string, labels ...string) *family {
+	return &family{name: name, help: help, kind: kind, labels: labels, series: make(map[string]*series)}
+}
+
+func newHistogram(name string, help string, buckets []float64, labels ...string) *family {
+	f := newFamily(name, help, kindHistogram, labels...)
+	f.buckets = buckets
+
+	return f
+}
+
+func (f *family) with(values ...string) *series {
+	key := strings.Join(values, "\x00")
+
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: values, buckets: make([]uint64, len(f.buckets))}
+		f.series[key] = s
+	}
+
+	return s
+}
+
+func (f *family) add(delta float64, values ...string) {
+	f.with(values...).value += delta
+}
+
+func (f *family) set(value float64, values ...string) {
+	f.with(values...).value = value
+}
+
+func (f *family) observe(value float64, values ...string) {
+	s := f.with(values...)
+	s.sum += value
+	s.count++
+
+	for idx, bound := range f.buckets {
+		if value <= bound {
+			s.buckets[idx]++
+		}
+	}
+}
+
+// deleteWhere removes the series whose label at idx equals value.
+func (f *family) deleteWhere(idx int, value string) {
+	for key, s := range f.series {
+		if s.labels[idx] == value {
+			delete(f.series, key)
+		}
+	}
+}
+
+func (f *family) write(w io.Writer) error {
+	if len(f.series) == 0 {
+		return nil
+	}
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", f.name, f.help, f.name, f.kind); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := f.series[key]
+
+		if f.kind != kindHistogram {
+			if _, err := fmt.Fprintf(w, "%s%s %s\n", f.name, f.labelString(s.labels, "", ""), formatFloat(s.value)); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		for idx, bound := range f.buckets {
+			if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, f.labelString(s.labels, "le", formatFloat(bound)), s.buckets[idx]); err != nil {
+				return err
+			}
+		}
+
+		if _, err := fmt.Fprintf(w, "%s_bucket%s %d\n%s_sum%s %s\n%s_count%s %d\n",
+			f.name, f.labelString(s.labels, "le", "+Inf"), s.count,
+			f.name, f.labelString(s.labels, "", ""), formatFloat(s.sum),
+			f.name, f.labelString(s.labels, "", ""), s.count); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (f *family) labelString(values []string, extraName string, extraValue string) string {
+	pairs := make([]string, 0, len(values)+1)
+	for idx, name := range f.labels {
+		pairs = append(pairs, name+"=\""+escape(values[idx])+"\"")
+	}
+
+	if extraName != "" {
+		pairs = append(pairs, extraName+"=\""+escape(extraValue)+"\"")
+	}
+
+	if len(pairs) == 0 {
+		return ""
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatFloat(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,308 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/zfullio/price-placements/v2/validation"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	KindNewErrors   = "new-errors"
+	KindLotDrop     = "lot-drop"
+	KindUnreachable = "unreachable"
+	KindRecovered   = "recovered"
+
+	defaultLotDrop     = 20
+	defaultDedupWindow = 24 * time.Hour
+	defaultTimeout     = 30 * time.Second
+)
+
+type WebhookConfig struct {
+	URL string `json:"url"`
+	// Format is json, slack or telegram.
+	Format string `json:"format"`
+	// ChatID is the Telegram chat, the URL is then https://api.telegram.org/bot<token>/sendMessage.
+	ChatID string `json:"chat_id"`
+}
+
+type Config struct {
+	Webhooks []WebhookConfig `json:"webhooks"`
+	// LotDropPercent is the drop of the lot count between checks that triggers a notification.
+	LotDropPercent float64 `json:"lot_drop_percent"`
+	// DedupWindow is the period an identical event is not sent again, e.g. "24h".
+	DedupWindow string `json:"dedup_window"`
+	// QuietHours like "22:00-08:00" holds notifications back until the period ends.
+	QuietHours string `json:"quiet_hours"`
+	TimeZone   string `json:"time_zone"`
+}
+
+// Check is the outcome of a feed check the notifier compares with the previous one.
+type Check struct {
+	Feed     string
+	Time     time.Time
+	Lots     int
+	Findings []validation.Finding
+	Error    string
+}
+
+type Event struct {
+	Feed     string               `json:"feed"`
+	Kind     string               `json:"kind"`
+	Time     time.Time            `json:"time"`
+	Message  string               `json:"message"`
+	Findings []validation.Finding `json:"findings,omitempty"`
+}
+
+type feedState struct {
+	lastGood    *Check
+	unreachable bool
+}
+
+// Notifier sends events about feed regressions to webhooks.
+type Notifier struct {
+	client   *http.Client
+	webhooks []WebhookConfig
+	lotDrop  float64
+	dedup    time.Duration
+	quiet    *quietHours
+	mu       sync.Mutex
+	states   map[string]*feedState
+	sent     map[string]time.Time
+	pending  []Event
+}
+
+// New returns a notifier posting with client. A nil client is replaced by a plain one of its own,
+// so that notifications don't share the timeouts, auth and metrics of the feed client.
+func New(config Config, client *http.Client) (*Notifier, error) {
+	if client == nil {
+		client = &http.Client{Timeout: defaultTimeout}
+	}
+
+	n := &Notifier{
+		client:   client,
+		webhooks: config.Webhooks,
+		lotDrop:  config.LotDropPercent,
+		dedup:    defaultDedupWindow,
+		states:   make(map[string]*feedState),
+		sent:     make(map[string]time.Time),
+	}
+
+	if n.lotDrop <= 0 {
+		n.lotDrop = defaultLotDrop
+	}
+
+	for _, webhook := range config.Webhooks {
+		switch webhook.Format {
+		case FormatJSON, FormatSlack, "":
+		case FormatTelegram:
+			if webhook.ChatID == "" {
+				return nil, fmt.Errorf("webhook %s needs chat_id for telegram", redact(webhook.URL))
+			}
+		default:
+			return nil, fmt.Errorf("webhook %s has unknown format %q", redact(webhook.URL), webhook.Format)
+		}
+	}
+
+	if config.DedupWindow != "" {
+		window, err := time.ParseDuration(config.DedupWindow)
+		if err != nil {
+			return nil, fmt.Errorf("invalid dedup_window. Error:%w", err)
+		}
+
+		n.dedup = window
+	}
+
+	if config.QuietHours != "" {
+		location := time.Local
+
+		if config.TimeZone != "" {
+			var err error
+
+			location, err = time.LoadLocation(config.TimeZone)
+			if err != nil {
+				return nil, fmt.Errorf("invalid time_zone. Error:%w", err)
+			}
+		}
+
+		quiet, err := parseQuietHours(config.QuietHours, location)
+		if err != nil {
+			return nil, err
+		}
+
+		n.quiet = quiet
+	}
+
+	return n, nil
+}
+
+// Seed sets the baseline of a feed without sending notifications, e.g. from stored results after a restart.
+func (n *Notifier) Seed(check Check) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	state := n.state(check.Feed)
+	state.unreachable = check.Error != ""
+
+	if check.Error == "" {
+		state.lastGood = &check
+	}
+}
+
+// Notify compares the check with the previous successful one of the feed and sends the resulting events.
+func (n *Notifier) Notify(ctx context.Context, check Check) error {
+	n.mu.Lock()
+
+	events := n.events(check)
+
+	n.pending = append(n.pending, events...)
+	if n.quiet != nil && n.quiet.contains(check.Time) {
+		n.mu.Unlock()
+
+		return nil
+	}
+
+	events = n.pending
+	n.pending = nil
+	n.mu.Unlock()
+
+	return n.send(ctx, events)
+}
+
+// Flush sends the events held back during quiet hours.
+func (n *Notifier) Flush(ctx context.Context) error {
+	n.mu.Lock()
+	events := n.pending
+	n.pending = nil
+	n.mu.Unlock()
+
+	return n.send(ctx, events)
+}
+
+// NextFlush returns when the events held back during quiet hours are due, ok is false if there are none.
+func (n *Notifier) NextFlush(now time.Time) (next time.Time, ok bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if len(n.pending) == 0 {
+		return time.Time{}, false
+	}
+
+	if n.quiet == nil || !n.quiet.contains(now) {
+		return now, true
+	}
+
+	return n.quiet.next(now), true
+}
+
+func (n *Notifier) events(check Check) []Event {
+	state := n.state(check.Feed)
+	events := make([]Event, 0)
+
+	if check.Error != "" {
+		if !state.unreachable {
+			state.unreachable = true
+			events = n.dedupe(events, Event{
+				Feed: check.Feed, Kind: KindUnreachable, Time: check.Time,
+				Message: fmt.Sprintf("feed %s is unreachable: %s", check.Feed, check.Error),
+			}, "")
+		}
+
+		return events
+	}
+
+	if state.unreachable {
+		state.unreachable = false
+		events = append(events, Event{
+			Feed: check.Feed, Kind: KindRecovered, Time: check.Time,
+			Message: fmt.Sprintf("feed %s is reachable again", check.Feed),
+		})
+	}
+
+	previous := state.lastGood
+	state.lastGood = &check
+
+	if previous == nil {
+		return events
+	}
+
+	added := make([]validation.Finding, 0)
+
+	for _, finding := range validation.Difference(check.Findings, previous.Findings) {
+		if finding.Severity == validation.SeverityError {
+			added = append(added, finding)
+		}
+	}
+
+	if len(added) > 0 {
+		events = n.dedupe(events, Event{
+			Feed: check.Feed, Kind: KindNewErrors, Time: check.Time, Findings: added,
+			Message: fmt.Sprintf("feed %s has %d new errors", check.Feed, len(added)),
+		}, fingerprint(added))
+	}
+
+	if previous.Lots > 0 {
+		drop := float64(previous.Lots-check.Lots) / float64(previous.Lots) * 100
+		if drop >= n.lotDrop {
+			events = n.dedupe(events, Event{
+				Feed: check.Feed, Kind: KindLotDrop, Time: check.Time,
+				Message: fmt.Sprintf("feed %s lots dropped by %.0f%%: %d -> %d", check.Feed, drop, previous.Lots, check.Lots),
+			}, fmt.Sprint(check.Lots))
+		}
+	}
+
+	return events
+}
+
+// dedupe appends the event unless an identical one was sent within the dedup window.
+func (n *Notifier) dedupe(events []Event, event Event, detail string) []Event {
+	key := event.Feed + "\x00" + event.Kind + "\x00" + detail
+
+	if sent, ok := n.sent[key]; ok && event.Time.Sub(sent) < n.dedup {
+		return events
+	}
+
+	n.sent[key] = event.Time
+
+	return append(events, event)
+}
+
+func (n *Notifier) state(feed string) *feedState {
+	state, ok := n.states[feed]
+	if !ok {
+		state = &feedState{}
+		n.states[feed] = state
+	}
+
+	return state
+}
+
+func (n *Notifier) send(ctx context.Context, events []Event) error {
+	errs := make([]error, 0)
+
+	for _, event := range events {
+		for _, webhook := range n.webhooks {
+			if err := post(ctx, n.client, webhook, event); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func fingerprint(findings []validation.Finding) string {
+	keys := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		keys = append(keys, finding.Rule+"/"+finding.ID+"/"+finding.Path)
+	}
+
+	sort.Strings(keys)
+
+	return strings.Join(keys, "\x00")
+}
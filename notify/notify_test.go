@@ -0,0 +1,221 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/zfullio/price-placements/v2/validation"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *recorder) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var event Event
+	if err := json.NewDecoder(req.Body).Decode(&event); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+}
+
+func (r *recorder) kinds() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	kinds := make([]string, 0, len(r.events))
+	for _, event := range r.events {
+		kinds = append(kinds, event.Kind)
+	}
+
+	return kinds
+}
+
+func newNotifier(t *testing.T, config Config) (*Notifier, *recorder) {
+	t.Helper()
+
+	events := &recorder{}
+	server := httptest.NewServer(events)
+	t.Cleanup(server.Close)
+
+	config.Webhooks = []WebhookConfig{{URL: server.URL}}
+
+	notifier, err := New(config, server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return notifier, events
+}
+
+func errorFinding(id string) validation.Finding {
+	return validation.Finding{Severity: validation.SeverityError, Rule: validation.RuleEmpty, ID: id, Path: "offer.Price"}
+}
+
+func TestNotifyEvents(t *testing.T) {
+	start := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		checks []Check
+		want   []string
+	}{
+		{
+			name:   "first check is the baseline",
+			checks: []Check{{Lots: 10, Findings: []validation.Finding{errorFinding("1")}}},
+			want:   []string{},
+		},
+		{
+			name: "new errors",
+			checks: []Check{
+				{Lots: 10},
+				{Lots: 10, Findings: []validation.Finding{errorFinding("1")}},
+			},
+			want: []string{KindNewErrors},
+		},
+		{
+			name: "same errors are deduplicated",
+			checks: []Check{
+				{Lots: 10},
+				{Lots: 10, Findings: []validation.Finding{errorFinding("1")}},
+				{Lots: 10},
+				{Lots: 10, Findings: []validation.Finding{errorFinding("1")}},
+			},
+			want: []string{KindNewErrors},
+		},
+		{
+			name:   "lot drop",
+			checks: []Check{{Lots: 100}, {Lots: 79}},
+			want:   []string{KindLotDrop},
+		},
+		{
+			name:   "small lot drop",
+			checks: []Check{{Lots: 100}, {Lots: 81}},
+			want:   []string{},
+		},
+		{
+			name:   "unreachable and recovered",
+			checks: []Check{{Lots: 10}, {Error: "timeout"}, {Error: "timeout"}, {Lots: 10}},
+			want:   []string{KindUnreachable, KindRecovered},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier, events := newNotifier(t, Config{})
+
+			for idx, check := range tt.checks {
+				check.Feed = "feed"
+				check.Time = start.Add(time.Duration(idx) * time.Hour)
+
+				if err := notifier.Notify(context.Background(), check); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got := events.kinds()
+			if len(got) != len(tt.want) {
+				t.Fatalf("got events %v, want %v", got, tt.want)
+			}
+
+			for idx := range got {
+				if got[idx] != tt.want[idx] {
+					t.Fatalf("got events %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNotifyQuietHours(t *testing.T) {
+	notifier, events := newNotifier(t, Config{QuietHours: "22:00-08:00", TimeZone: "UTC"})
+	night := time.Date(2024, 3, 1, 23, 0, 0, 0, time.UTC)
+
+	if _, ok := notifier.NextFlush(night); ok {
+		t.Fatal("NextFlush is due without held back events")
+	}
+
+	if err := notifier.Notify(context.Background(), Check{Feed: "feed", Time: night, Error: "timeout"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := events.kinds(); len(got) != 0 {
+		t.Fatalf("events %v were sent during quiet hours", got)
+	}
+
+	next, ok := notifier.NextFlush(night)
+	if want := time.Date(2024, 3, 2, 8, 0, 0, 0, time.UTC); !ok || !next.Equal(want) {
+		t.Fatalf("NextFlush = %s, %v, want %s", next, ok, want)
+	}
+
+	if next, ok := notifier.NextFlush(night.Add(10 * time.Hour)); !ok || !next.Equal(night.Add(10*time.Hour)) {
+		t.Fatalf("NextFlush after quiet hours = %s, %v", next, ok)
+	}
+
+	if err := notifier.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := events.kinds(); len(got) != 1 || got[0] != KindUnreachable {
+		t.Fatalf("flushed events %v, want [%s]", got, KindUnreachable)
+	}
+
+	if _, ok := notifier.NextFlush(night); ok {
+		t.Fatal("NextFlush is due after Flush")
+	}
+}
+
+func TestWebhookErrorsHideToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	closed := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		webhook WebhookConfig
+	}{
+		{name: "not accepted", webhook: WebhookConfig{URL: server.URL + "/services/T000/B000/SECRET123", Format: FormatSlack}},
+		{name: "unreachable", webhook: WebhookConfig{URL: closed.URL + "/botSECRET123/sendMessage", Format: FormatTelegram, ChatID: "1"}},
+		{name: "invalid", webhook: WebhookConfig{URL: "http://[::1/botSECRET123/sendMessage"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier, err := New(Config{Webhooks: []WebhookConfig{tt.webhook}}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			notifier.Seed(Check{Feed: "shop", Lots: 10})
+
+			err = notifier.Notify(context.Background(), Check{Feed: "shop", Error: "timeout"})
+			if err == nil {
+				t.Fatal("no error")
+			}
+
+			if strings.Contains(err.Error(), "SECRET123") {
+				t.Errorf("error %q contains the token", err)
+			}
+		})
+	}
+
+	_, err := New(Config{Webhooks: []WebhookConfig{{URL: "https://api.telegram.org/botSECRET123/sendMessage", Format: FormatTelegram}}}, nil)
+	if err == nil || strings.Contains(err.Error(), "SECRET123") {
+		t.Errorf("config error %v contains the token", err)
+	}
+}
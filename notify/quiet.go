@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type quietHours struct {
+	start    time.Duration
+	end      time.Duration
+	location *time.Location
+}
+
+func parseQuietHours(value string, location *time.Location) (*quietHours, error) {
+	startPart, endPart, ok := strings.Cut(value, "-")
+	if !ok {
+		return nil, fmt.Errorf("quiet hours %q must look like 22:00-08:00", value)
+	}
+
+	start, err := parseClock(startPart)
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := parseClock(endPart)
+	if err != nil {
+		return nil, err
+	}
+
+	return &quietHours{start: start, end: end, location: location}, nil
+}
+
+func parseClock(value string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("invalid quiet hours time %q. Error:%w", value, err)
+	}
+
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// contains reports whether t falls into the quiet period, which may span midnight.
+func (q *quietHours) contains(t time.Time) bool {
+	t = t.In(q.location)
+	clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+
+	if q.start <= q.end {
+		return clock >= q.start && clock < q.end
+	}
+
+	return clock >= q.start || clock < q.end
+}
+
+// next returns the end of the quiet period containing t.
+func (q *quietHours) next(t time.Time) time.Time {
+	t = t.In(q.location)
+	end := time.Date(t.Year(), t.Month(), t.Day(), int(q.end/time.Hour), int(q.end%time.Hour/time.Minute), 0, 0, q.location)
+
+	if !end.After(t) {
+		end = time.Date(t.Year(), t.Month(), t.Day()+1, end.Hour(), end.Minute(), 0, 0, q.location)
+	}
+
+	return end
+}
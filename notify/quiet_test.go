@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuietHours(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		at       string
+		contains bool
+		next     string
+	}{
+		{name: "overnight before midnight", value: "22:00-08:00", at: "2024-03-01 23:30", contains: true, next: "2024-03-02 08:00"},
+		{name: "overnight after midnight", value: "22:00-08:00", at: "2024-03-02 07:59", contains: true, next: "2024-03-02 08:00"},
+		{name: "overnight at end", value: "22:00-08:00", at: "2024-03-02 08:00", contains: false},
+		{name: "overnight daytime", value: "22:00-08:00", at: "2024-03-02 12:00", contains: false},
+		{name: "daytime", value: "12:00-14:30", at: "2024-03-02 13:00", contains: true, next: "2024-03-02 14:30"},
+		{name: "daytime at start", value: "12:00-14:30", at: "2024-03-02 12:00", contains: true, next: "2024-03-02 14:30"},
+		{name: "daytime before", value: "12:00-14:30", at: "2024-03-02 11:59", contains: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			quiet, err := parseQuietHours(tt.value, time.UTC)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			at := parseTime(t, tt.at)
+
+			if got := quiet.contains(at); got != tt.contains {
+				t.Errorf("contains(%s) = %v, want %v", tt.at, got, tt.contains)
+			}
+
+			if tt.next == "" {
+				return
+			}
+
+			if got := quiet.next(at); !got.Equal(parseTime(t, tt.next)) {
+				t.Errorf("next(%s) = %s, want %s", tt.at, got, tt.next)
+			}
+		})
+	}
+}
+
+func TestParseQuietHoursInvalid(t *testing.T) {
+	for _, value := range []string{"", "22:00", "22-08", "25:00-08:00", "22:00-8am"} {
+		if _, err := parseQuietHours(value, time.UTC); err == nil {
+			t.Errorf("parseQuietHours(%q) has no error", value)
+		}
+	}
+}
+
+func parseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+
+	parsed, err := time.ParseInLocation("2006-01-02 15:04", value, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return parsed
+}
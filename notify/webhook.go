@@ -0,0 +1,99 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	FormatJSON     = "json"
+	FormatSlack    = "slack"
+	FormatTelegram = "telegram"
+
+	maxListedFindings = 10
+)
+
+func post(ctx context.Context, client *http.Client, webhook WebhookConfig, event Event) error {
+	var payload any
+
+	switch webhook.Format {
+	case FormatSlack:
+		payload = map[string]string{"text": text(event, "*")}
+	case FormatTelegram:
+		payload = map[string]any{"chat_id": webhook.ChatID, "text": text(event, ""), "disable_web_page_preview": true}
+	default:
+		payload = event
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("can't encode notification. Error:%w", err)
+	}
+
+	target := redact(webhook.URL)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("can't send notification to %s. Error:%w", target, redactError(err, target))
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	response, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("can't send notification to %s. Error:%w", target, redactError(err, target))
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("notification to %s not accepted. Status:%s", target, response.Status)
+	}
+
+	return nil
+}
+
+// redact returns the scheme and host of a webhook URL: Slack and Telegram pass the token in the path.
+func redact(rawURL string) string {
+	target, err := url.Parse(rawURL)
+	if err != nil || target.Host == "" {
+		return "webhook"
+	}
+
+	return target.Scheme + "://" + target.Host
+}
+
+// redactError replaces the URL of a client error, which is the full webhook URL, with target.
+func redactError(err error, target string) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = target
+	}
+
+	return err
+}
+
+// text formats the event for chats, bold wraps the feed name when the chat supports it.
+func text(event Event, bold string) string {
+	var builder strings.Builder
+
+	builder.WriteString(bold + event.Feed + bold + ": " + event.Message)
+
+	for idx, finding := range event.Findings {
+		if idx == maxListedFindings {
+			builder.WriteString(fmt.Sprintf("\n… and %d more", len(event.Findings)-maxListedFindings))
+
+			break
+		}
+
+		builder.WriteString("\n• " + finding.String())
+	}
+
+	return builder.String()
+}
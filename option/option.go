@@ -0,0 +1,113 @@
+package option
+
+import (
+	"crypto/tls"
+	"github.com/zfullio/price-placements/v2/decoding"
+	"github.com/zfullio/price-placements/v2/transport"
+	"github.com/zfullio/price-placements/v2/validation"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Options are the settings shared by the feeds of every platform.
+type Options struct {
+	Logger     *slog.Logger
+	Transport  []transport.Option
+	DecodeMode decoding.Mode
+	MaxAge     time.Duration
+	Rules      validation.Rules
+}
+
+type Option func(*Options)
+
+func Apply(opts ...Option) Options {
+	options := Options{Logger: transport.DiscardLogger()}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return options
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *Options) {
+		if logger == nil {
+			return
+		}
+
+		o.Logger = logger
+		o.Transport = append(o.Transport, transport.WithLogger(logger))
+	}
+}
+
+// WithTransport passes options straight to the feed transport.
+func WithTransport(opts ...transport.Option) Option {
+	return func(o *Options) {
+		o.Transport = append(o.Transport, opts...)
+	}
+}
+
+func WithHeaders(header http.Header) Option {
+	return WithTransport(transport.WithHeaders(header))
+}
+
+func WithUserAgent(userAgent string) Option {
+	return WithTransport(transport.WithUserAgent(userAgent))
+}
+
+func WithBasicAuth(username string, password string) Option {
+	return WithTransport(transport.WithBasicAuth(username, password))
+}
+
+func WithAuth(auth transport.Auth) Option {
+	return WithTransport(transport.WithAuth(auth))
+}
+
+func WithTLSConfig(config *tls.Config) Option {
+	return WithTransport(transport.WithTLSConfig(config))
+}
+
+func WithTimeout(timeout time.Duration) Option {
+	return WithTransport(transport.WithTimeout(timeout))
+}
+
+func WithMaxBodySize(size int64) Option {
+	return WithTransport(transport.WithMaxBodySize(size))
+}
+
+func WithReadTimeout(timeout time.Duration) Option {
+	return WithTransport(transport.WithReadTimeout(timeout))
+}
+
+func WithProgress(progress transport.Progress) Option {
+	return WithTransport(transport.WithProgress(progress))
+}
+
+func WithDecodeMode(mode decoding.Mode) Option {
+	return func(o *Options) {
+		o.DecodeMode = mode
+	}
+}
+
+// WithMaxAge sets the age after which the feed is reported as stale.
+func WithMaxAge(maxAge time.Duration) Option {
+	return func(o *Options) {
+		o.MaxAge = maxAge
+	}
+}
+
+// WithRules reports only the findings of the rules.
+func WithRules(rules ...string) Option {
+	return func(o *Options) {
+		o.Rules.Include = append(o.Rules.Include, rules...)
+	}
+}
+
+// WithoutRules drops the findings of the rules.
+func WithoutRules(rules ...string) Option {
+	return func(o *Options) {
+		o.Rules.Exclude = append(o.Rules.Exclude, rules...)
+	}
+}
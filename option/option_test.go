@@ -0,0 +1,121 @@
+package option
+
+import (
+	"context"
+	"github.com/zfullio/price-placements/v2/decoding"
+	"github.com/zfullio/price-placements/v2/transport"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestApply(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name  string
+		opts  []Option
+		check func(t *testing.T, options Options)
+	}{
+		{
+			name: "defaults",
+			check: func(t *testing.T, options Options) {
+				if options.Logger == nil || options.Logger.Enabled(context.Background(), slog.LevelError) {
+					t.Error("default logger is not a discard logger")
+				}
+
+				if len(options.Transport) != 0 || options.DecodeMode != decoding.Strict || options.MaxAge != 0 {
+					t.Errorf("unexpected defaults %+v", options)
+				}
+			},
+		},
+		{
+			name: "nil logger",
+			opts: []Option{WithLogger(nil)},
+			check: func(t *testing.T, options Options) {
+				if options.Logger == nil || len(options.Transport) != 0 {
+					t.Errorf("nil logger is not ignored: %+v", options)
+				}
+			},
+		},
+		{
+			name: "logger",
+			opts: []Option{WithLogger(logger)},
+			check: func(t *testing.T, options Options) {
+				if options.Logger != logger {
+					t.Error("logger is not set")
+				}
+
+				if transport.New(nil, options.Transport...).Logger() != logger {
+					t.Error("logger is not passed to the transport")
+				}
+			},
+		},
+		{
+			name: "rules",
+			opts: []Option{WithRules("empty", "zero"), WithoutRules("stale"), WithRules("area")},
+			check: func(t *testing.T, options Options) {
+				if !slices.Equal(options.Rules.Include, []string{"empty", "zero", "area"}) {
+					t.Errorf("included rules are %v", options.Rules.Include)
+				}
+
+				if !slices.Equal(options.Rules.Exclude, []string{"stale"}) {
+					t.Errorf("excluded rules are %v", options.Rules.Exclude)
+				}
+			},
+		},
+		{
+			name: "decode mode and max age",
+			opts: []Option{WithDecodeMode(decoding.Lenient), WithMaxAge(time.Hour)},
+			check: func(t *testing.T, options Options) {
+				if options.DecodeMode != decoding.Lenient || options.MaxAge != time.Hour {
+					t.Errorf("unexpected options %+v", options)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.check(t, Apply(tt.opts...))
+		})
+	}
+}
+
+func TestTransportOptions(t *testing.T) {
+	var request *http.Request
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		request = r
+	}))
+	defer server.Close()
+
+	options := Apply(
+		WithHeaders(http.Header{"X-Feed": []string{"a", "b"}}),
+		WithUserAgent("feed-checker"),
+		WithBasicAuth("user", "secret"),
+	)
+
+	response, err := transport.New(nil, options.Transport...).Get(context.Background(), server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response.Body.Close()
+
+	if got := request.Header.Values("X-Feed"); !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("got headers %v", got)
+	}
+
+	if got := request.UserAgent(); got != "feed-checker" {
+		t.Errorf("got user agent %q", got)
+	}
+
+	if username, password, ok := request.BasicAuth(); !ok || username != "user" || password != "secret" {
+		t.Errorf("got basic auth %q %q %v", username, password, ok)
+	}
+}
@@ -0,0 +1,77 @@
+package platform
+
+import (
+	"context"
+	"fmt"
+	"github.com/zfullio/price-placements/v2/avito"
+	"github.com/zfullio/price-placements/v2/cian"
+	"github.com/zfullio/price-placements/v2/dom_click"
+	"github.com/zfullio/price-placements/v2/freshness"
+	"github.com/zfullio/price-placements/v2/option"
+	"github.com/zfullio/price-placements/v2/realty"
+	"github.com/zfullio/price-placements/v2/summary"
+	"github.com/zfullio/price-placements/v2/validation"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	Avito    = "avito"
+	Cian     = "cian"
+	Realty   = "realty"
+	DomClick = "domclick"
+)
+
+// Feed is the behaviour shared by the feeds of every platform.
+type Feed interface {
+	Get(ctx context.Context) error
+	Read(r io.Reader) error
+	ReadFile(name string) error
+	Restore(body []byte, header http.Header) error
+	Validate() ([]validation.Finding, error)
+	CheckStructure() ([]validation.Finding, error)
+	CheckFreshness(now time.Time) []validation.Finding
+	Raw() []byte
+	Header() http.Header
+	ContentHash() string
+	Lots() int
+	Summaries() []summary.Lot
+	Stats() summary.Stats
+	Dates() freshness.Freshness
+}
+
+func Names() []string {
+	return []string{Avito, Cian, Realty, DomClick}
+}
+
+func New(platform string, client *http.Client, url string, opts ...option.Option) (Feed, error) {
+	switch platform {
+	case Avito:
+		return avitoFeed{avito.NewFeed(client, url, opts...)}, nil
+	case Cian:
+		return cianFeed{cian.NewFeed(client, url, opts...)}, nil
+	case Realty:
+		return realtyFeed{realty.NewFeed(client, url, opts...)}, nil
+	case DomClick:
+		return domClickFeed{domclick.NewFeed(client, url, opts...)}, nil
+	default:
+		return nil, fmt.Errorf("unknown platform %q", platform)
+	}
+}
+
+type avitoFeed struct{ *avito.Feed }
+
+func (f avitoFeed) Dates() freshness.Freshness { return f.Freshness }
+
+type cianFeed struct{ *cian.Feed }
+
+func (f cianFeed) Dates() freshness.Freshness { return f.Freshness }
+
+type realtyFeed struct{ *realty.Feed }
+
+func (f realtyFeed) Dates() freshness.Freshness { return f.Freshness }
+
+type domClickFeed struct{ *domclick.Feed }
+
+func (f domClickFeed) Dates() freshness.Freshness { return f.Freshness }
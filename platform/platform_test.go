@@ -0,0 +1,74 @@
+package platform
+
+import (
+	"context"
+	"errors"
+	"github.com/zfullio/price-placements/v2/decoding"
+	"github.com/zfullio/price-placements/v2/option"
+	"github.com/zfullio/price-placements/v2/transport"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNewAppliesOptions(t *testing.T) {
+	var userAgent string
+
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		userAgent = r.UserAgent()
+	}))
+	defer server.Close()
+
+	for _, name := range Names() {
+		t.Run(name, func(t *testing.T) {
+			userAgent = ""
+
+			feed, err := New(name, nil, server.URL, option.WithUserAgent("feed-checker"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_ = feed.Get(context.Background())
+
+			if userAgent != "feed-checker" {
+				t.Errorf("got user agent %q", userAgent)
+			}
+		})
+	}
+
+	if _, err := New("unknown", nil, server.URL); err == nil {
+		t.Error("unknown platform is accepted")
+	}
+}
+
+func TestTypedErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	for _, name := range Names() {
+		t.Run(name, func(t *testing.T) {
+			feed, err := New(name, nil, server.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var notFetchedErr *transport.NotFetchedError
+			if _, err := feed.Validate(); !errors.As(err, &notFetchedErr) {
+				t.Errorf("Validate before Get returned %v, want a NotFetchedError", err)
+			}
+
+			var statusErr *transport.HTTPStatusError
+			if err := feed.Get(context.Background()); !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+				t.Errorf("Get returned %v, want a 404 HTTPStatusError", err)
+			}
+
+			var decodeErr *decoding.DecodeError
+			if err := feed.Read(strings.NewReader("<feed><unclosed></feed>")); !errors.As(err, &decodeErr) {
+				t.Errorf("Read returned %v, want a DecodeError", err)
+			}
+		})
+	}
+}
@@ -3,35 +3,75 @@ package realty
 //
 
 import (
+	"bytes"
 	"context"
 	"encoding/xml"
-	"errors"
 	"fmt"
+	"github.com/zfullio/price-placements/v2/decoding"
+	"github.com/zfullio/price-placements/v2/freshness"
+	"github.com/zfullio/price-placements/v2/option"
+	"github.com/zfullio/price-placements/v2/schema"
+	"github.com/zfullio/price-placements/v2/summary"
 	"github.com/zfullio/price-placements/v2/transport"
 	"github.com/zfullio/price-placements/v2/validation"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"os"
+	"strings"
 	"time"
 )
 
+const (
+	platformName = "realty"
+
+	lotElement = "offer"
+
+	Namespace = "http://webmaster.yandex.ru/schemas/feed/realty/2010-06"
+
+	unitSquareMeterRu = "кв. м"
+	unitSquareMeterEn = "sq. m"
+
+	typeSale = "продажа"
+	typeRent = "аренда"
+
+	categoryRoom       = "комната"
+	categoryFlat       = "квартира"
+	categoryCommercial = "коммерческая"
+)
+
 type Feed struct {
-	client       *http.Client
+	transport    *transport.Transport
+	logger       *slog.Logger
 	url          string
 	isGet        bool
+	raw          []byte
+	decoded      decoding.Result
+	rules        validation.Rules
+	header       http.Header
+	DecodeMode   decoding.Mode
 	LastModified time.Time
+	Freshness    freshness.Freshness
+	MaxAge       time.Duration
 	Data         Data
 }
 
 type Data struct {
-	GenerationDate string  `xml:"generation-date"`
-	Offer          []Offer `xml:"offer"`
+	XMLName        xml.Name `xml:"realty-feed"`
+	GenerationDate string   `xml:"generation-date"`
+	Offer          []Offer  `xml:"offer"`
 }
 
-func NewFeed(client *http.Client, url string) *Feed {
+func NewFeed(client *http.Client, url string, opts ...option.Option) *Feed {
+	options := option.Apply(opts...)
+
 	return &Feed{
-		client: client,
-		url:    url,
+		transport:  transport.New(client, options.Transport...),
+		logger:     options.Logger.With(slog.String("platform", platformName), slog.String("url", url)),
+		url:        url,
+		rules:      options.Rules,
+		DecodeMode: options.DecodeMode,
+		MaxAge:     options.MaxAge,
 	}
 }
 
@@ -39,71 +79,136 @@ type Offer struct {
 	InternalID string `xml:"internal-id,attr"`
 	Image      []struct {
 		Tag string `xml:"tag,attr"`
+		URL string `xml:",chardata"`
 	} `xml:"image"`
 	Type           string   `xml:"type"`
 	PropertyType   string   `xml:"property-type"`
 	Category       string   `xml:"category"`
+	CommercialType []string `xml:"commercial-type"`
 	URL            string   `xml:"url"`
 	WindowView     string   `xml:"window-view"`
-	CeilingHeight  []string `xml:"ceiling-height"`
+	CeilingHeight  float32  `xml:"ceiling-height"`
 	Description    string   `xml:"description"`
 	CreationDate   string   `xml:"creation-date"`
 	Vas            []vas    `xml:"vas"`
 	LastUpdateDate string   `xml:"last-update-date"`
 	ExpireDate     string   `xml:"expire-date"`
-	Location       struct {
-		Country      string `xml:"country"`
-		Region       string `xml:"region"`
-		Address      string `xml:"address"`
-		LocalityName string `xml:"locality-name"`
-		Latitude     string `xml:"latitude"`
-		Longitude    string `xml:"longitude"`
-		Direction    string `xml:"direction"`
-		Distance     string `xml:"distance"`
-		Metro        struct {
+	VideoReview    struct {
+		YoutubeVideoReviewURL string `xml:"youtube-video-review-url"`
+	} `xml:"video-review"`
+	Location struct {
+		Country         string `xml:"country"`
+		Region          string `xml:"region"`
+		District        string `xml:"district"`
+		LocalityName    string `xml:"locality-name"`
+		SubLocalityName string `xml:"sub-locality-name"`
+		Address         string `xml:"address"`
+		Apartment       string `xml:"apartment"`
+		Latitude        string `xml:"latitude"`
+		Longitude       string `xml:"longitude"`
+		Direction       string `xml:"direction"`
+		Distance        string `xml:"distance"`
+		RailwayStation  string `xml:"railway-station"`
+		Metro           struct {
 			Name            string `xml:"name"`
 			TimeOnTransport string `xml:"time-on-transport"`
 			TimeOnFoot      string `xml:"time-on-foot"`
 		} `xml:"metro"`
 	} `xml:"location"`
 	SalesAgent struct {
+		Name         string `xml:"name"`
 		Category     string `xml:"category"`
 		Organization string `xml:"organization"`
 		Phone        string `xml:"phone"`
+		Email        string `xml:"email"`
+		URL          string `xml:"url"`
+		Photo        string `xml:"photo"`
 	} `xml:"sales-agent"`
 	Price struct {
-		Value    float32 `xml:"value"`
-		Currency string  `xml:"currency"`
+		Value        validation.Float `xml:"value"`
+		Currency     string           `xml:"currency"`
+		Period       string           `xml:"period"`
+		Unit         string           `xml:"unit"`
+		TaxationForm string           `xml:"taxation-form"`
 	} `xml:"price"`
-	NewFlat          string                 `xml:"new-flat"`
-	DealStatus       string                 `xml:"deal-status"`
-	BuiltYear        int64                  `xml:"built-year"`
-	ReadyQuarter     int64                  `xml:"ready-quarter"`
-	Area             Value                  `xml:"area"`
-	RoomSpace        []Value                `xml:"room-space"`
-	LivingSpace      Value                  `xml:"living-space"`
-	KitchenSpace     Value                  `xml:"kitchen-space"`
-	Renovation       string                 `xml:"renovation"`
-	Rooms            int64                  `xml:"rooms"`
-	RubbishChute     string                 `xml:"rubbish-chute"`
-	FloorsTotal      int64                  `xml:"floors-total"`
-	Floor            int64                  `xml:"floor"`
-	BuildingName     string                 `xml:"building-name"`
-	BuildingType     string                 `xml:"building-type"`
-	Mortgage         string                 `xml:"mortgage"`
-	BuildingState    string                 `xml:"building-state"`
-	Lift             string                 `xml:"lift"`
-	BathroomUnit     string                 `xml:"bathroom-unit"`
-	YandexBuildingID int64                  `xml:"yandex-building-id"`
-	YandexHouseID    validation.CustomInt64 `xml:"yandex-house-id"`
-	BuildingSection  string                 `xml:"building-section"`
-	Balcony          string                 `xml:"balcony"`
-	OpenPlan         string                 `xml:"open-plan"`
+	NewFlat                string         `xml:"new-flat"`
+	DealStatus             string         `xml:"deal-status"`
+	Haggle                 string         `xml:"haggle"`
+	Mortgage               string         `xml:"mortgage"`
+	Prepayment             int64          `xml:"prepayment"`
+	RentPledge             string         `xml:"rent-pledge"`
+	AgentFee               float32        `xml:"agent-fee"`
+	Commission             float32        `xml:"commission"`
+	UtilitiesIncluded      string         `xml:"utilities-included"`
+	WithPets               string         `xml:"with-pets"`
+	WithChildren           string         `xml:"with-children"`
+	BuiltYear              int64          `xml:"built-year"`
+	ReadyQuarter           int64          `xml:"ready-quarter"`
+	Area                   Value          `xml:"area"`
+	RoomSpace              []Value        `xml:"room-space"`
+	LivingSpace            Value          `xml:"living-space"`
+	KitchenSpace           Value          `xml:"kitchen-space"`
+	LotArea                Value          `xml:"lot-area"`
+	LotType                string         `xml:"lot-type"`
+	Renovation             string         `xml:"renovation"`
+	Rooms                  int64          `xml:"rooms"`
+	RoomsOffered           int64          `xml:"rooms-offered"`
+	RoomsType              string         `xml:"rooms-type"`
+	Studio                 string         `xml:"studio"`
+	Apartments             string         `xml:"apartments"`
+	RubbishChute           string         `xml:"rubbish-chute"`
+	FloorsTotal            int64          `xml:"floors-total"`
+	Floor                  int64          `xml:"floor"`
+	BuildingName           string         `xml:"building-name"`
+	BuildingType           string         `xml:"building-type"`
+	BuildingSeries         string         `xml:"building-series"`
+	BuildingPhase          string         `xml:"building-phase"`
+	BuildingState          string         `xml:"building-state"`
+	Lift                   string         `xml:"lift"`
+	BathroomUnit           string         `xml:"bathroom-unit"`
+	Parking                string         `xml:"parking"`
+	ParkingPlaces          int64          `xml:"parking-places"`
+	ParkingPlacePrice      float32        `xml:"parking-place-price"`
+	ParkingType            string         `xml:"parking-type"`
+	GuardedBuilding        string         `xml:"guarded-building"`
+	IsElite                string         `xml:"is-elite"`
+	YandexBuildingID       int64          `xml:"yandex-building-id"`
+	YandexHouseID          validation.Int `xml:"yandex-house-id"`
+	BuildingSection        string         `xml:"building-section"`
+	Balcony                string         `xml:"balcony"`
+	OpenPlan               string         `xml:"open-plan"`
+	RoomFurniture          string         `xml:"room-furniture"`
+	KitchenFurniture       string         `xml:"kitchen-furniture"`
+	Television             string         `xml:"television"`
+	WashingMachine         string         `xml:"washing-machine"`
+	Dishwasher             string         `xml:"dishwasher"`
+	Refrigerator           string         `xml:"refrigerator"`
+	AirConditioner         string         `xml:"air-conditioner"`
+	Internet               string         `xml:"internet"`
+	CommercialBuildingType string         `xml:"commercial-building-type"`
+	Purpose                []string       `xml:"purpose"`
+	PurposeWarehouse       []string       `xml:"purpose-warehouse"`
+	OfficeClass            string         `xml:"office-class"`
+	EntranceType           string         `xml:"entrance-type"`
+	PhoneLines             int64          `xml:"phone-lines"`
+	AddingPhoneOnRequest   string         `xml:"adding-phone-on-request"`
+	ElectricCapacity       float32        `xml:"electric-capacity"`
+	WindowType             string         `xml:"window-type"`
+	TwentyFourSeven        string         `xml:"twenty-four-seven"`
+	EatingFacilities       string         `xml:"eating-facilities"`
+	Ventilation            string         `xml:"ventilation"`
+	FireAlarm              string         `xml:"fire-alarm"`
+	Security               string         `xml:"security"`
+	HeatingSupply          string         `xml:"heating-supply"`
+	WaterSupply            string         `xml:"water-supply"`
+	SewerageSupply         string         `xml:"sewerage-supply"`
+	ElectricitySupply      string         `xml:"electricity-supply"`
+	GasSupply              string         `xml:"gas-supply"`
 }
 
 type Value struct {
-	Value float32 `xml:"value"`
-	Unit  string  `xml:"unit"`
+	Value validation.Float `xml:"value"`
+	Unit  string           `xml:"unit"`
 }
 
 type vas struct {
@@ -118,127 +223,487 @@ func (f *Feed) Get(ctx context.Context) error {
 		return fmt.Errorf("can't get feed info. Error:%w", err)
 	}
 
-	resp, err := transport.GetResponse(ctx, f.client, f.url)
+	resp, err := f.transport.Get(ctx, f.url)
 	if err != nil {
 		return fmt.Errorf("can't get feed data. Error:%w", err)
 	}
 
 	defer resp.Body.Close()
 
-	responseBody, err := io.ReadAll(resp.Body)
+	responseBody, err := f.transport.ReadBody(resp)
 	if err != nil {
 		return err
 	}
 
-	err = xml.Unmarshal(responseBody, &f.Data)
+	if f.Freshness.Header.IsZero() {
+		f.Freshness.Header, err = freshness.ParseHeader(resp.Header)
+		if err != nil {
+			return err
+		}
+	}
+
+	f.header = resp.Header
+
+	return f.load(responseBody)
+}
+
+func (f *Feed) ReadFile(name string) error {
+	body, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("can't read feed file. Error:%w", err)
+	}
+
+	f.Freshness.FileModTime, err = freshness.FileModTime(name)
 	if err != nil {
 		return err
 	}
 
-	if time.Time.IsZero(f.LastModified) {
-		f.LastModified, err = time.Parse(time.RFC3339Nano, f.Data.GenerationDate)
-		if err != nil {
-			return err
-		}
+	return f.load(body)
+}
+
+// Restore decodes body as if it was fetched with the response header, e.g. from an archived snapshot.
+func (f *Feed) Restore(body []byte, header http.Header) error {
+	var err error
+
+	f.Freshness.Header, err = freshness.ParseHeader(header)
+	if err != nil {
+		return err
+	}
+
+	f.header = header
+
+	return f.load(body)
+}
+
+func (f *Feed) Read(r io.Reader) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("can't read feed. Error:%w", err)
+	}
+
+	return f.load(body)
+}
+
+func (f *Feed) load(body []byte) error {
+	var err error
+
+	f.Data = Data{}
+	f.raw = body
+
+	f.decoded, err = decoding.Decode(body, &f.Data, f.DecodeMode, lotElement)
+	if err != nil {
+		return err
 	}
 
 	f.isGet = true
+	f.Freshness.Generated = freshness.ParseDate(f.Data.GenerationDate)
+	f.Freshness.LatestOffer = f.latestOfferUpdate()
+	f.LastModified, _ = f.Freshness.Resolve()
+
+	f.logger.Debug("feed decoded", slog.Int("bytes", len(body)), slog.Int("lots", f.Lots()),
+		slog.Int("decode_findings", len(f.decoded.Findings)), slog.Time("last_modified", f.LastModified))
 
 	return nil
 }
 
+func (f *Feed) IsStale(now time.Time) bool {
+	return f.Freshness.IsStale(now, f.MaxAge)
+}
+
+func (f *Feed) CheckFreshness(now time.Time) []validation.Finding {
+	return f.rules.Filter(f.Freshness.Check(now, f.MaxAge))
+}
+
+func (f *Feed) Raw() []byte {
+	return f.raw
+}
+
+// Header returns the response header of the last download.
+func (f *Feed) Header() http.Header {
+	return f.header
+}
+
+func (f *Feed) ContentHash() string {
+	return freshness.Hash(f.raw)
+}
+
+func (f *Feed) Lots() int {
+	return len(f.Data.Offer)
+}
+
+// lotIDs returns the lot IDs in document order.
+func (f *Feed) lotIDs() []string {
+	ids := make([]string, 0, len(f.Data.Offer))
+	for _, lot := range f.Data.Offer {
+		ids = append(ids, lot.InternalID)
+	}
+
+	return ids
+}
+
+func (f *Feed) Summaries() []summary.Lot {
+	lots := make([]summary.Lot, 0, len(f.Data.Offer))
+
+	for idx, lot := range f.Data.Offer {
+		images := make([]string, 0, len(lot.Image))
+		for _, image := range lot.Image {
+			images = append(images, strings.TrimSpace(image.URL))
+		}
+
+		lots = append(lots, summary.Lot{
+			ID:       lot.InternalID,
+			Title:    summary.Title(lot.Type, lot.Category, lot.Location.Address),
+			Images:   images,
+			Position: f.decoded.Positions.At(lotElement, idx),
+		})
+	}
+
+	return lots
+}
+
+func (f *Feed) Stats() summary.Stats {
+	offers := make([]summary.Offer, 0, len(f.Data.Offer))
+
+	for _, lot := range f.Data.Offer {
+		offer := summary.Offer{
+			Rooms:      summary.Rooms(lot.Rooms),
+			Building:   lot.BuildingName,
+			Section:    lot.BuildingSection,
+			Decoration: lot.Renovation,
+			Price:      lot.Price.Value.Value,
+			Area:       lot.Area.Value.Value,
+		}
+
+		switch {
+		case isTrue(lot.Studio):
+			offer.Rooms = summary.RoomsStudio
+		case isTrue(lot.OpenPlan):
+			offer.Rooms = summary.RoomsFreeLayout
+		}
+
+		// The price may be given per square meter.
+		if isKnownUnit(lot.Price.Unit) {
+			offer.Price *= offer.Area
+		}
+
+		for _, image := range lot.Image {
+			switch image.Tag {
+			case "plan":
+				offer.HasPlan = true
+			case "floor-plan":
+			default:
+				offer.Photos++
+			}
+		}
+
+		offers = append(offers, offer)
+	}
+
+	return summary.Compute(offers)
+}
+
+// CheckOfferDates reports offers not updated within maxAge and offers whose expire-date has passed.
+func (f *Feed) CheckOfferDates(now time.Time, maxAge time.Duration) []validation.Finding {
+	findings := make([]validation.Finding, 0)
+
+	if maxAge <= 0 {
+		maxAge = freshness.DefaultOfferMaxAge
+	}
+
+	for idx, lot := range f.Data.Offer {
+		start := len(findings)
+
+		if updated := freshness.ParseDate(lot.LastUpdateDate); !updated.IsZero() && now.Sub(updated) > maxAge {
+			validation.Add(&findings, validation.RuleOutdated, lot.InternalID, "offer.LastUpdateDate",
+				fmt.Sprintf("field offer.LastUpdateDate %s is older than %s", lot.LastUpdateDate, maxAge))
+		}
+
+		if expire := freshness.ParseDate(lot.ExpireDate); !expire.IsZero() && expire.Before(now) {
+			validation.Add(&findings, validation.RuleOutdated, lot.InternalID, "offer.ExpireDate",
+				fmt.Sprintf("field offer.ExpireDate %s has passed", lot.ExpireDate))
+		}
+
+		validation.SetPosition(findings[start:], f.decoded.Positions.At(lotElement, idx))
+	}
+
+	return f.rules.Filter(findings)
+}
+
+func (f *Feed) latestOfferUpdate() time.Time {
+	dates := make([]time.Time, 0, len(f.Data.Offer))
+	for _, lot := range f.Data.Offer {
+		dates = append(dates, freshness.ParseDate(lot.LastUpdateDate))
+	}
+
+	return freshness.Latest(dates...)
+}
+
 func (f *Feed) GetInfo(ctx context.Context) error {
-	resp, err := transport.GetOnlyHeader(ctx, f.client, f.url)
+	resp, err := f.transport.Head(ctx, f.url)
 	if err != nil {
 		return err
 	}
 
 	defer resp.Body.Close()
 
-	attributeLastModified := resp.Header.Get(transport.HeaderLastModified)
-	if attributeLastModified != "" {
-		lastModifiedDate, err := time.Parse(time.RFC1123, resp.Header.Get(transport.HeaderLastModified))
-		if err != nil {
-			return err
-		}
-		f.LastModified = lastModifiedDate
-	} else {
-		log.Println("Header not contains `Last-Modified`")
+	lastModified, err := freshness.ParseHeader(resp.Header)
+	if err != nil {
+		return err
+	}
+
+	if lastModified.IsZero() {
+		f.logger.DebugContext(ctx, "header not contains `Last-Modified`")
 	}
 
+	f.Freshness.Header = lastModified
+	f.LastModified, _ = f.Freshness.Resolve()
+
 	return nil
 }
 
 func (f *Feed) Check() ([]string, error) {
+	findings, err := f.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	return validation.Strings(findings), nil
+}
+
+func (f *Feed) Validate() ([]validation.Finding, error) {
 	if !f.isGet {
-		return nil, errors.New("feed not got")
+		return nil, &transport.NotFetchedError{URL: f.url}
 	}
 
-	results := make([]string, 0)
+	results := make([]validation.Finding, 0, len(f.decoded.Findings))
+	results = append(results, f.decoded.LotFindings(lotElement, f.lotIDs())...)
 
 	if len(f.Data.Offer) < 2 {
-		results = append(results, validation.MsgEmptyFeed)
-		return results, nil
+		validation.Add(&results, validation.RuleFeedSize, "", "realty-feed", validation.MsgEmptyFeed)
+		return f.rules.Filter(results), nil
+	}
+
+	if f.Data.XMLName.Space != Namespace {
+		validation.Add(&results, validation.RuleFormat, "", "realty-feed", fmt.Sprintf("realty-feed namespace is '%s', expected '%s'", f.Data.XMLName.Space, Namespace))
 	}
 
 	for idx, lot := range f.Data.Offer {
-		if lot.InternalID == "" {
-			results = append(results, fmt.Sprintf("field InternalID is empty. Position: %v", idx))
-		}
-		tags := make(map[string]bool)
-		for _, image := range lot.Image {
-			if tags[image.Tag] {
-				continue
-			}
-			tags[image.Tag] = true
-		}
+		start := len(results)
+		validation.CheckFilledWithPos(idx, "offer", "InternalID", lot.InternalID, &results)
+
+		id := lot.InternalID
+		validation.CheckFilledWithID(id, "offer", "Type", lot.Type, &results)
+		validation.CheckOneOfWithID(id, "offer", "Type", lot.Type, []string{typeSale, typeRent}, &results)
+		validation.CheckFilledWithID(id, "offer", "Category", lot.Category, &results)
+		validation.CheckFilledWithID(id, "offer", "CreationDate", lot.CreationDate, &results)
+		validation.CheckFilledWithID(id, "offer.Location", "Country", lot.Location.Country, &results)
+		validation.CheckFilledWithID(id, "offer.Location", "Address", lot.Location.Address, &results)
+		validation.CheckFilledWithID(id, "offer.SalesAgent", "Phone", lot.SalesAgent.Phone, &results)
+		validation.CheckFilledWithID(id, "offer.SalesAgent", "Category", lot.SalesAgent.Category, &results)
+		validation.CheckNonZeroWithID(id, "offer.Price", "Value", lot.Price.Value, &results)
+		validation.CheckFilledWithID(id, "offer.Price", "Currency", lot.Price.Currency, &results)
 
-		if _, ok := tags["plan"]; !ok {
-			results = append(results, fmt.Sprintf("tag 'plan' for image is not found. InternalID: %v", lot.InternalID))
+		if lot.SalesAgent.Email != "" && !strings.Contains(lot.SalesAgent.Email, "@") {
+			validation.Add(&results, validation.RuleFormat, id, "offer.SalesAgent.Email", "field offer.SalesAgent.Email is not an email")
 		}
 
-		if _, ok := tags["floor-plan"]; !ok {
-			results = append(results, fmt.Sprintf("tag 'floor-plan' for image is not found. InternalID: %v", lot.InternalID))
+		switch lot.Type {
+		case typeSale:
+			validation.CheckFilledWithID(id, "offer", "DealStatus", lot.DealStatus, &results)
+			validation.CheckOneOfWithID(id, "offer", "DealStatus", lot.DealStatus, []string{
+				"первичная продажа", "primary sale", "прямая продажа", "sale", "переуступка", "reassignment",
+				"встречная продажа", "countersale", "первичная продажа вторички", "primary sale of secondary",
+			}, &results)
+		case typeRent:
+			validation.CheckFilledWithID(id, "offer.Price", "Period", lot.Price.Period, &results)
+			validation.CheckOneOfWithID(id, "offer.Price", "Period", lot.Price.Period, []string{"день", "day", "месяц", "month"}, &results)
+			validation.CheckOneOfWithID(id, "offer", "DealStatus", lot.DealStatus, []string{
+				"прямая аренда", "direct rent", "субаренда", "subrent", "продажа права аренды", "sale of lease rights",
+			}, &results)
 		}
 
-		id := lot.InternalID
-		validation.CheckStringWithID(id, "offer", "Type", lot.Type, &results)
-		validation.CheckStringWithID(id, "offer", "PropertyType", lot.PropertyType, &results)
-		validation.CheckStringWithID(id, "offer", "CreationDate", lot.CreationDate, &results)
-		validation.CheckStringWithID(id, "offer.Location", "Country", lot.Location.Country, &results)
-		validation.CheckStringWithID(id, "offer.Location", "Address", lot.Location.Address, &results)
-		validation.CheckStringWithID(id, "offer.SalesAgent", "Phone", lot.SalesAgent.Phone, &results)
-		validation.CheckStringWithID(id, "offer.SalesAgent", "Category", lot.SalesAgent.Category, &results)
-		validation.CheckStringWithID(id, "offer", "DealStatus", lot.DealStatus, &results)
-		validation.CheckZeroWithID(id, "offer.Price", "Value", lot.Price.Value, &results)
-		validation.CheckStringWithID(id, "offer.Price", "Currency", lot.Price.Currency, &results)
-		validation.CheckZeroWithID(id, "offer.Area", "Value", lot.Area.Value, &results)
-		validation.CheckStringWithID(id, "offer.Area", "Unit", lot.Area.Unit, &results)
-		validation.CheckZeroWithID(id, "offer", "Rooms", int(lot.Rooms), &results)
-		validation.CheckStringWithID(id, "offer", "NewFlat", lot.NewFlat, &results)
-		validation.CheckZeroWithID(id, "offer", "Floor", int(lot.Floor), &results)
-		validation.CheckZeroWithID(id, "offer", "FloorsTotal", int(lot.FloorsTotal), &results)
-		validation.CheckStringWithID(id, "offer", "BuildingName", lot.BuildingName, &results)
-		validation.CheckZeroWithID(id, "offer", "YandexBuildingID", int(lot.YandexBuildingID), &results)
-		validation.CheckStringWithID(id, "offer", "BuildingState", lot.BuildingState, &results)
-		validation.CheckZeroWithID(id, "offer", "BuiltYear", int(lot.BuiltYear), &results)
-		validation.CheckZeroWithID(id, "offer", "ReadyQuarter", int(lot.ReadyQuarter), &results)
-
-		if lot.LivingSpace.Value == 0 && lot.OpenPlan != "1" {
-			results = append(results, fmt.Sprintf("field LivingSpace.Value is empty. InternalID: %v", lot.InternalID))
+		// Offers without a category are checked as flats, the only category the feed was checked for before.
+		switch category(lot.Category) {
+		case categoryCommercial:
+			checkCommercial(lot, &results)
+		case categoryFlat, categoryRoom, "":
+			checkLiving(lot, &results)
+		default:
+			validation.CheckNonZeroWithID(id, "offer.Area", "Value", lot.Area.Value, &results)
 		}
-		if lot.BuiltYear < int64(time.Now().Year()) && lot.BuildingState == "unfinished" {
-			results = append(results, fmt.Sprintf("BuildingState == unfinished for %v. InternalID: %v", lot.BuiltYear, lot.InternalID))
+
+		if len(lot.Image) < 3 {
+			validation.Add(&results, validation.RuleImages, id, "offer.Image", fmt.Sprintf("field Image contains '%v' items", len(lot.Image)))
 		}
-		if lot.Floor > lot.FloorsTotal {
-			results = append(results, fmt.Sprintf("field Floor is bigger than FloorsTotal. InternalID: %v", lot.InternalID))
+
+		validation.SetPosition(results[start:], f.decoded.Positions.At(lotElement, idx))
+	}
+
+	return f.rules.Filter(results), nil
+}
+
+func checkLiving(lot Offer, results *[]validation.Finding) {
+	id := lot.InternalID
+
+	validation.CheckFilledWithID(id, "offer", "PropertyType", lot.PropertyType, results)
+	validation.CheckNonZeroWithID(id, "offer.Area", "Value", lot.Area.Value, results)
+	validation.CheckFilledWithID(id, "offer.Area", "Unit", lot.Area.Unit, results)
+	validation.CheckNumberWithID(id, "offer", "Rooms", int(lot.Rooms), results)
+	validation.CheckNumberWithID(id, "offer", "Floor", int(lot.Floor), results)
+	validation.CheckNumberWithID(id, "offer", "FloorsTotal", int(lot.FloorsTotal), results)
+
+	validation.CheckFilledWithID(id, "offer", "NewFlat", lot.NewFlat, results)
+
+	if category(lot.Category) != categoryRoom || isTrue(lot.NewFlat) {
+		checkBuilding(lot, results)
+	}
+
+	if category(lot.Category) == categoryRoom {
+		validation.CheckNumberWithID(id, "offer", "RoomsOffered", int(lot.RoomsOffered), results)
+
+		if lot.RoomsOffered > lot.Rooms {
+			validation.Add(results, validation.RuleConsistency, id, "offer.RoomsOffered", "field RoomsOffered is bigger than Rooms")
 		}
-		if int64(len(lot.RoomSpace)) > lot.Rooms {
-			results = append(results, fmt.Sprintf("field RoomSpace contains more values than Rooms. InternalID: %v", lot.InternalID))
+	}
+
+	if lot.Floor > lot.FloorsTotal {
+		validation.Add(results, validation.RuleConsistency, id, "offer.Floor", "field Floor is bigger than FloorsTotal")
+	}
+
+	roomSpaces := make([]float64, 0, len(lot.RoomSpace))
+	for _, room := range lot.RoomSpace {
+		roomSpaces = append(roomSpaces, room.Value.Value)
+	}
+
+	layout := validation.Layout{
+		Field:          "Rooms",
+		Rooms:          validation.RoomsFromCount(lot.Rooms),
+		Studio:         isTrue(lot.Studio),
+		OpenPlan:       isTrue(lot.OpenPlan),
+		RoomType:       lot.RoomsType,
+		RoomAreas:      roomSpaces,
+		RoomAreasField: "RoomSpace",
+		Total:          lot.Area.Value.Value,
+	}
+
+	validation.CheckLivingArea(id, "offer", "LivingSpace.Value", layout, lot.LivingSpace.Value.Value, results)
+	validation.CheckRooms(id, "offer", layout, results)
+	validation.CheckAreaSum(id, "offer", lot.Area.Value.Value, lot.LivingSpace.Value.Value, lot.KitchenSpace.Value.Value, results)
+	validation.CheckRoomsAreaSum(id, "offer", lot.LivingSpace.Value.Value, roomSpaces, results)
+
+	checkUnits(lot, results)
+}
+
+// checkBuilding checks the plans and the building of flats and of rooms in new buildings.
+func checkBuilding(lot Offer, results *[]validation.Finding) {
+	id := lot.InternalID
+
+	tags := make(map[string]bool)
+	for _, image := range lot.Image {
+		tags[image.Tag] = true
+	}
+
+	if !tags["plan"] {
+		validation.Add(results, validation.RuleImages, id, "offer.Image", "tag 'plan' for image is not found")
+	}
+
+	if !tags["floor-plan"] {
+		validation.Add(results, validation.RuleImages, id, "offer.Image", "tag 'floor-plan' for image is not found")
+	}
+
+	validation.CheckFilledWithID(id, "offer", "BuildingName", lot.BuildingName, results)
+	validation.CheckNumberWithID(id, "offer", "YandexBuildingID", int(lot.YandexBuildingID), results)
+	validation.CheckFilledWithID(id, "offer", "BuildingState", lot.BuildingState, results)
+	validation.CheckNumberWithID(id, "offer", "BuiltYear", int(lot.BuiltYear), results)
+	validation.CheckNumberWithID(id, "offer", "ReadyQuarter", int(lot.ReadyQuarter), results)
+
+	if lot.BuiltYear < int64(time.Now().Year()) && lot.BuildingState == "unfinished" {
+		validation.Add(results, validation.RuleDeadline, id, "offer.BuildingState", fmt.Sprintf("BuildingState == unfinished for %v", lot.BuiltYear))
+	}
+}
+
+func checkCommercial(lot Offer, results *[]validation.Finding) {
+	id := lot.InternalID
+
+	if len(lot.CommercialType) == 0 {
+		validation.Add(results, validation.RuleEmpty, id, "offer.CommercialType", "field CommercialType is empty")
+	}
+
+	validation.CheckNonZeroWithID(id, "offer.Area", "Value", lot.Area.Value, results)
+	validation.CheckFilledWithID(id, "offer.Area", "Unit", lot.Area.Unit, results)
+	validation.CheckOneOfWithID(id, "offer", "OfficeClass", lot.OfficeClass, []string{"A", "A+", "B", "B+", "C", "C+"}, results)
+	validation.CheckOneOfWithID(id, "offer", "EntranceType", lot.EntranceType, []string{"common", "separate"}, results)
+	validation.CheckOneOfWithID(id, "offer.Price", "TaxationForm", lot.Price.TaxationForm, []string{"НДС", "VAT", "УСН", "USN"}, results)
+
+	if lot.FloorsTotal != 0 && lot.Floor > lot.FloorsTotal {
+		validation.Add(results, validation.RuleConsistency, id, "offer.Floor", "field Floor is bigger than FloorsTotal")
+	}
+}
+
+func category(value string) string {
+	switch strings.ToLower(value) {
+	case categoryRoom, "room":
+		return categoryRoom
+	case categoryFlat, "flat":
+		return categoryFlat
+	case categoryCommercial, "commercial":
+		return categoryCommercial
+	default:
+		return strings.ToLower(value)
+	}
+}
+
+func isTrue(value string) bool {
+	switch strings.ToLower(value) {
+	case "да", "true", "1", "+":
+		return true
+	default:
+		return false
+	}
+}
+
+func checkUnits(lot Offer, results *[]validation.Finding) {
+	if lot.Area.Unit != "" && !isKnownUnit(lot.Area.Unit) {
+		validation.Add(results, validation.RuleEnum, lot.InternalID, "offer.Area.Unit", fmt.Sprintf("field offer.Area.Unit has unknown value '%s'", lot.Area.Unit))
+	}
+
+	values := []Value{lot.LivingSpace, lot.KitchenSpace}
+	fields := []string{"LivingSpace", "KitchenSpace"}
+
+	for idx, room := range lot.RoomSpace {
+		values = append(values, room)
+		fields = append(fields, fmt.Sprintf("RoomSpace[%d]", idx))
+	}
+
+	for idx, value := range values {
+		if value.Value.Value == 0 {
+			continue
 		}
-		if len(lot.Image) < 3 {
-			results = append(results, fmt.Sprintf("field Image contains '%v' items. InternalID: %v", len(lot.Image), lot.InternalID))
+
+		switch {
+		case value.Unit == "":
+			validation.Add(results, validation.RuleEmpty, lot.InternalID, "offer."+fields[idx]+".Unit", fmt.Sprintf("field offer.%s.Unit is empty", fields[idx]))
+		case !isKnownUnit(value.Unit):
+			validation.Add(results, validation.RuleEnum, lot.InternalID, "offer."+fields[idx]+".Unit", fmt.Sprintf("field offer.%s.Unit has unknown value '%s'", fields[idx], value.Unit))
+		case lot.Area.Unit != "" && value.Unit != lot.Area.Unit:
+			validation.Add(results, validation.RuleConsistency, lot.InternalID, "offer."+fields[idx]+".Unit", fmt.Sprintf("field offer.%s.Unit '%s' differs from Area.Unit '%s'", fields[idx], value.Unit, lot.Area.Unit))
 		}
 	}
-	return results, nil
+}
+
+func isKnownUnit(unit string) bool {
+	return unit == unitSquareMeterRu || unit == unitSquareMeterEn
+}
+
+func (f *Feed) CheckStructure() ([]validation.Finding, error) {
+	if !f.isGet {
+		return nil, &transport.NotFetchedError{URL: f.url}
+	}
+
+	findings, err := schema.Validate(bytes.NewReader(f.raw), Schema())
+	if err != nil {
+		return nil, err
+	}
+
+	return f.rules.Filter(findings), nil
 }
@@ -0,0 +1,101 @@
+package realty
+
+import (
+	"encoding/xml"
+	"github.com/zfullio/price-placements/v2/validation"
+	"testing"
+)
+
+func TestCheckLivingReportsAreaOnce(t *testing.T) {
+	lot := Offer{
+		InternalID:  "1",
+		Type:        typeSale,
+		Category:    categoryFlat,
+		Rooms:       3,
+		Area:        area(30),
+		LivingSpace: area(20),
+	}
+
+	var results []validation.Finding
+
+	checkLiving(lot, &results)
+
+	count := 0
+
+	for _, finding := range results {
+		if finding.Rule == validation.RuleArea && finding.Path == "offer.TotalArea" {
+			count++
+		}
+	}
+
+	if count != 1 {
+		t.Errorf("got %v TotalArea findings, want 1: %v", count, results)
+	}
+}
+
+func area(value float64) Value {
+	return Value{Value: validation.Float{Value: value, State: validation.StateSet}, Unit: unitSquareMeterRu}
+}
+
+func TestValidateFlatRequirements(t *testing.T) {
+	tests := []struct {
+		name    string
+		lot     Offer
+		want    []string
+		wantNot []string
+	}{
+		{
+			name: "secondary flat for rent",
+			lot:  Offer{Type: typeRent, Category: categoryFlat, NewFlat: "нет"},
+			want: []string{
+				"offer.BuildingName", "offer.YandexBuildingID", "offer.BuildingState", "offer.BuiltYear",
+				"offer.ReadyQuarter", "offer.Image",
+			},
+		},
+		{
+			name: "flat without new-flat",
+			lot:  Offer{Type: typeRent, Category: categoryFlat},
+			want: []string{"offer.NewFlat", "offer.BuildingName"},
+		},
+		{
+			name: "offer without category",
+			lot:  Offer{Type: typeSale, Rooms: 2, Area: area(20)},
+			want: []string{"offer.Category", "offer.TotalArea", "offer.BuildingName", "offer.NewFlat"},
+		},
+		{
+			name:    "secondary room",
+			lot:     Offer{Type: typeSale, Category: categoryRoom, NewFlat: "нет"},
+			want:    []string{"offer.RoomsOffered"},
+			wantNot: []string{"offer.BuildingName", "offer.YandexBuildingID"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.lot.InternalID = "1"
+			feed := &Feed{isGet: true, Data: Data{XMLName: xml.Name{Space: Namespace}, Offer: []Offer{tt.lot, tt.lot}}}
+
+			findings, err := feed.Validate()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			paths := make(map[string]bool, len(findings))
+			for _, finding := range findings {
+				paths[finding.Path] = true
+			}
+
+			for _, path := range tt.want {
+				if !paths[path] {
+					t.Errorf("no finding for %s in %v", path, findings)
+				}
+			}
+
+			for _, path := range tt.wantNot {
+				if paths[path] {
+					t.Errorf("unexpected finding for %s in %v", path, findings)
+				}
+			}
+		})
+	}
+}
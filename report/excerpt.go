@@ -0,0 +1,117 @@
+package report
+
+import (
+	"bytes"
+	"github.com/zfullio/price-placements/v2/validation"
+	"sort"
+	"unicode/utf8"
+)
+
+const (
+	excerptLines = 2
+	excerptBytes = 240
+	maxExcerpts  = 1000
+	ellipsis     = "…"
+)
+
+// excerpt is the source around a position: the element starting at the position is Tag,
+// Before and After hold up to excerptLines lines or excerptBytes bytes of context.
+type excerpt struct {
+	validation.Position
+	Before string
+	Tag    string
+	After  string
+}
+
+// excerpts returns the excerpts of the finding and lot positions in document order, at most maxExcerpts.
+func excerpts(report Report) []excerpt {
+	if len(report.Source) == 0 {
+		return nil
+	}
+
+	positions := make(map[int64]validation.Position)
+
+	add := func(position validation.Position) {
+		if position.IsValid() && position.Offset < int64(len(report.Source)) {
+			positions[position.Offset] = position
+		}
+	}
+
+	for _, finding := range report.Findings {
+		add(finding.Position)
+	}
+
+	for _, lot := range report.Lots {
+		add(lot.Position)
+	}
+
+	offsets := make([]int64, 0, len(positions))
+	for offset := range positions {
+		offsets = append(offsets, offset)
+	}
+
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+
+	result := make([]excerpt, 0, min(len(offsets), maxExcerpts))
+	for _, offset := range offsets[:min(len(offsets), maxExcerpts)] {
+		result = append(result, excerptAt(report.Source, positions[offset]))
+	}
+
+	return result
+}
+
+func excerptAt(source []byte, position validation.Position) excerpt {
+	offset := int(position.Offset)
+
+	start := offset
+	for lines := 0; start > 0 && offset-start < excerptBytes; start-- {
+		if source[start-1] == '\n' {
+			if lines++; lines > excerptLines {
+				break
+			}
+		}
+	}
+
+	tagEnd := offset + min(len(source)-offset, excerptBytes)
+	if idx := bytes.IndexByte(source[offset:tagEnd], '>'); idx >= 0 {
+		tagEnd = offset + idx + 1
+	}
+
+	end := tagEnd
+	for lines := 0; end < len(source) && end-tagEnd < excerptBytes; end++ {
+		if source[end] == '\n' {
+			if lines++; lines > excerptLines {
+				break
+			}
+		}
+	}
+
+	start, end = runeStart(source, start), runeStart(source, end)
+	tagEnd = max(runeStart(source, tagEnd), offset)
+
+	result := excerpt{
+		Position: position,
+		Before:   string(source[start:offset]),
+		Tag:      string(source[offset:tagEnd]),
+		After:    string(source[tagEnd:end]),
+	}
+
+	if start > 0 && source[start-1] != '\n' {
+		result.Before = ellipsis + result.Before
+	}
+
+	if end < len(source) && source[end] != '\n' {
+		result.After += ellipsis
+	}
+
+	return result
+}
+
+// runeStart moves idx back to the start of the UTF-8 sequence it points into.
+func runeStart(source []byte, idx int) int {
+	for idx > 0 && idx < len(source) && !utf8.RuneStart(source[idx]) {
+		idx--
+	}
+
+	return idx
+}
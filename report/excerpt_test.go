@@ -0,0 +1,81 @@
+package report
+
+import (
+	"bytes"
+	"github.com/zfullio/price-placements/v2/summary"
+	"github.com/zfullio/price-placements/v2/validation"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestExcerptAt(t *testing.T) {
+	multiline := "<feed>\n<a/>\n<b/>\n<offer id=\"1\">\n<c/>\n<d/>\n<e/>\n</feed>"
+	minified := "<feed>" + strings.Repeat("<a>x</a>", 100) + "<offer id=\"1\"><price>10</price></offer>" + strings.Repeat("<b>y</b>", 100) + "</feed>"
+	cyrillic := strings.Repeat("д", 200) + "<offer>"
+
+	tests := []struct {
+		name   string
+		source string
+		want   excerpt
+	}{
+		{
+			name:   "multiline",
+			source: multiline,
+			want:   excerpt{Before: "<a/>\n<b/>\n", Tag: "<offer id=\"1\">", After: "\n<c/>\n<d/>"},
+		},
+		{
+			name:   "minified",
+			source: minified,
+			want: excerpt{
+				Before: ellipsis + strings.Repeat("<a>x</a>", 30),
+				Tag:    "<offer id=\"1\">",
+				After:  "<price>10</price></offer>" + strings.Repeat("<b>y</b>", 26) + "<b>y</b" + ellipsis,
+			},
+		},
+		{
+			name:   "utf-8",
+			source: cyrillic,
+			want:   excerpt{Before: ellipsis + strings.Repeat("д", 120), Tag: "<offer>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			offset := int64(strings.Index(tt.source, "<offer"))
+			got := excerptAt([]byte(tt.source), validation.Position{Offset: offset, Line: 1, Column: 1})
+
+			if got.Before != tt.want.Before || got.Tag != tt.want.Tag || got.After != tt.want.After {
+				t.Fatalf("got %q %q %q, want %q %q %q", got.Before, got.Tag, got.After, tt.want.Before, tt.want.Tag, tt.want.After)
+			}
+		})
+	}
+}
+
+func TestRenderHTMLEmbedsExcerpts(t *testing.T) {
+	source := "<feed>" + strings.Repeat("<a>x</a>", 10000) + "<offer><price>0</price></offer></feed>"
+	offset := int64(strings.Index(source, "<offer>"))
+	position := validation.Position{Offset: offset, Line: 1, Column: int(offset) + 1}
+
+	report := Report{
+		Title:    "feed",
+		Lots:     []summary.Lot{{ID: "1", Position: position}},
+		Findings: []validation.Finding{{Severity: validation.SeverityError, Rule: validation.RuleEmpty, ID: "1", Message: "field price is empty", Position: position}},
+		Source:   []byte(source),
+	}
+
+	var buffer bytes.Buffer
+	if err := RenderHTML(&buffer, report); err != nil {
+		t.Fatal(err)
+	}
+
+	page := buffer.String()
+	if strings.Count(page, "&lt;a&gt;x&lt;/a&gt;") > 2*excerptBytes/len("<a>x</a>") {
+		t.Fatal("page embeds the whole source")
+	}
+
+	anchor := `id="S` + strconv.FormatInt(offset, 10) + `"`
+	if !strings.Contains(page, anchor) || !strings.Contains(page, `href="#S`+strconv.FormatInt(offset, 10)+`"`) {
+		t.Fatalf("no excerpt link for offset %d", offset)
+	}
+}
@@ -0,0 +1,280 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/zfullio/price-placements/v2/validation"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchemaVersion changes whenever a field of the JSON export changes meaning or is removed.
+const SchemaVersion = 1
+
+const (
+	FormatJSON  = "json"
+	FormatCSV   = "csv"
+	FormatJUnit = "junit"
+	FormatHTML  = "html"
+)
+
+type jsonReport struct {
+	SchemaVersion int           `json:"schema_version"`
+	Title         string        `json:"title"`
+	Platform      string        `json:"platform"`
+	Generated     time.Time     `json:"generated"`
+	Summary       jsonSummary   `json:"summary"`
+	Findings      []jsonFinding `json:"findings"`
+}
+
+type jsonSummary struct {
+	Lots     int `json:"lots"`
+	Findings int `json:"findings"`
+	Errors   int `json:"errors"`
+	Warnings int `json:"warnings"`
+}
+
+type jsonFinding struct {
+	LotID    string `json:"lot_id"`
+	Field    string `json:"field"`
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Offset   int64  `json:"offset"`
+}
+
+// Write exports the report in one of the formats json, csv, junit or html.
+func Write(w io.Writer, format string, report Report) error {
+	switch format {
+	case FormatJSON, "":
+		return WriteJSON(w, report)
+	case FormatCSV:
+		return WriteCSV(w, report)
+	case FormatJUnit:
+		return WriteJUnit(w, report)
+	case FormatHTML:
+		return RenderHTML(w, report)
+	default:
+		return fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+func ContentType(format string) (string, error) {
+	switch format {
+	case FormatJSON, "":
+		return "application/json; charset=utf-8", nil
+	case FormatCSV:
+		return "text/csv; charset=utf-8", nil
+	case FormatJUnit:
+		return "application/xml; charset=utf-8", nil
+	case FormatHTML:
+		return "text/html; charset=utf-8", nil
+	default:
+		return "", fmt.Errorf("unknown report format %q", format)
+	}
+}
+
+func WriteJSON(w io.Writer, report Report) error {
+	output := jsonReport{
+		SchemaVersion: SchemaVersion,
+		Title:         report.Title,
+		Platform:      report.Platform,
+		Generated:     report.Generated,
+		Summary: jsonSummary{
+			Lots:     len(report.Lots),
+			Findings: len(report.Findings),
+			Errors:   countSeverity(report.Findings, validation.SeverityError),
+			Warnings: countSeverity(report.Findings, validation.SeverityWarning),
+		},
+		Findings: make([]jsonFinding, 0, len(report.Findings)),
+	}
+
+	for _, finding := range report.lotFindings() {
+		output.Findings = append(output.Findings, jsonFinding{
+			LotID:    finding.ID,
+			Field:    finding.Path,
+			Rule:     finding.Rule,
+			Severity: string(finding.Severity),
+			Message:  finding.Message,
+			Line:     finding.Position.Line,
+			Column:   finding.Position.Column,
+			Offset:   finding.Position.Offset,
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(output); err != nil {
+		return fmt.Errorf("can't write JSON report. Error:%w", err)
+	}
+
+	return nil
+}
+
+func WriteCSV(w io.Writer, report Report) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{"lot_id", "field", "rule", "severity", "message", "line", "column"}); err != nil {
+		return fmt.Errorf("can't write CSV report. Error:%w", err)
+	}
+
+	for _, finding := range report.lotFindings() {
+		record := []string{
+			finding.ID,
+			finding.Path,
+			finding.Rule,
+			string(finding.Severity),
+			finding.Message,
+			strconv.Itoa(finding.Position.Line),
+			strconv.Itoa(finding.Position.Column),
+		}
+
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("can't write CSV report. Error:%w", err)
+		}
+	}
+
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("can't write CSV report. Error:%w", err)
+	}
+
+	return nil
+}
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Timestamp string      `xml:"timestamp,attr,omitempty"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes one testcase per lot plus one for the feed itself. A testcase fails on error findings,
+// warnings are reported in system-out.
+func WriteJUnit(w io.Writer, report Report) error {
+	lots, other := report.Group()
+
+	suite := junitSuite{Name: report.Title}
+	if !report.Generated.IsZero() {
+		suite.Timestamp = report.Generated.Format("2006-01-02T15:04:05")
+	}
+
+	suite.Cases = append(suite.Cases, junitTestCase("feed", report.Platform, other))
+
+	for idx, lot := range lots {
+		name := lot.Lot.ID
+		if name == "" {
+			name = fmt.Sprintf("lot #%d", idx+1)
+		}
+
+		suite.Cases = append(suite.Cases, junitTestCase(name, report.Platform, lot.Findings))
+	}
+
+	for _, testCase := range suite.Cases {
+		if testCase.Failure != nil {
+			suite.Failures++
+		}
+	}
+
+	suite.Tests = len(suite.Cases)
+
+	output := junitSuites{Name: report.Title, Tests: suite.Tests, Failures: suite.Failures, Suites: []junitSuite{suite}}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return fmt.Errorf("can't write JUnit report. Error:%w", err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(output); err != nil {
+		return fmt.Errorf("can't write JUnit report. Error:%w", err)
+	}
+
+	return nil
+}
+
+func junitTestCase(name string, className string, findings []validation.Finding) junitCase {
+	testCase := junitCase{Name: name, ClassName: className}
+	errs := make([]string, 0)
+	warnings := make([]string, 0)
+
+	for _, finding := range findings {
+		line := fmt.Sprintf("[%s] %s", finding.Rule, finding.String())
+		if finding.Severity == validation.SeverityError {
+			errs = append(errs, line)
+		} else {
+			warnings = append(warnings, line)
+		}
+	}
+
+	if len(errs) > 0 {
+		testCase.Failure = &junitFailure{
+			Message: fmt.Sprintf("%d validation errors", len(errs)),
+			Type:    "validation",
+			Text:    strings.Join(errs, "\n"),
+		}
+	}
+
+	testCase.SystemOut = strings.Join(warnings, "\n")
+
+	return testCase
+}
+
+// lotFindings returns findings with the lot ID filled from the lot position when it is missing.
+func (r Report) lotFindings() []validation.Finding {
+	lots, other := r.Group()
+	findings := make([]validation.Finding, 0, len(r.Findings))
+	findings = append(findings, other...)
+
+	for _, lot := range lots {
+		for _, finding := range lot.Findings {
+			finding.ID = lot.Lot.ID
+			findings = append(findings, finding)
+		}
+	}
+
+	return findings
+}
+
+func countSeverity(findings []validation.Finding, severity validation.Severity) int {
+	count := 0
+
+	for _, finding := range findings {
+		if finding.Severity == severity {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,115 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"github.com/zfullio/price-placements/v2/summary"
+	"github.com/zfullio/price-placements/v2/validation"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func testReport() Report {
+	second := validation.Position{Offset: 40, Line: 3, Column: 1}
+
+	return Report{
+		Title:     "feed",
+		Platform:  "realty",
+		Generated: time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+		Lots: []summary.Lot{
+			{ID: "1", Position: validation.Position{Offset: 10, Line: 2, Column: 1}},
+			{ID: "2", Position: second},
+		},
+		Findings: []validation.Finding{
+			{Severity: validation.SeverityError, Rule: validation.RuleFeedSize, Path: "realty-feed", Message: "feed is empty"},
+			{Severity: validation.SeverityError, Rule: validation.RuleEmpty, ID: "1", Path: "offer.Price", Message: "field offer.Price is empty"},
+			{Severity: validation.SeverityWarning, Rule: validation.RuleDecode, Path: "offer/rooms", Message: "field offer/rooms has invalid integer value 'x'", Position: second},
+		},
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Write(&buffer, FormatJSON, testReport()); err != nil {
+		t.Fatal(err)
+	}
+
+	var output jsonReport
+	if err := json.Unmarshal(buffer.Bytes(), &output); err != nil {
+		t.Fatal(err)
+	}
+
+	if output.SchemaVersion != SchemaVersion || output.Summary != (jsonSummary{Lots: 2, Findings: 3, Errors: 2, Warnings: 1}) {
+		t.Fatalf("unexpected report %+v", output)
+	}
+
+	ids := make([]string, 0, len(output.Findings))
+	for _, finding := range output.Findings {
+		ids = append(ids, finding.LotID)
+	}
+
+	if !reflect.DeepEqual(ids, []string{"", "1", "2"}) || output.Findings[2].Line != 3 || output.Findings[2].Offset != 40 {
+		t.Fatalf("unexpected findings %+v", output.Findings)
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Write(&buffer, FormatCSV, testReport()); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buffer).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{
+		{"lot_id", "field", "rule", "severity", "message", "line", "column"},
+		{"", "realty-feed", validation.RuleFeedSize, "error", "feed is empty", "0", "0"},
+		{"1", "offer.Price", validation.RuleEmpty, "error", "field offer.Price is empty", "0", "0"},
+		{"2", "offer/rooms", validation.RuleDecode, "warning", "field offer/rooms has invalid integer value 'x'", "3", "1"},
+	}
+
+	if !reflect.DeepEqual(records, want) {
+		t.Fatalf("got %q, want %q", records, want)
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := Write(&buffer, FormatJUnit, testReport()); err != nil {
+		t.Fatal(err)
+	}
+
+	var output junitSuites
+	if err := xml.Unmarshal(buffer.Bytes(), &output); err != nil {
+		t.Fatal(err)
+	}
+
+	if output.Tests != 3 || output.Failures != 2 || len(output.Suites) != 1 {
+		t.Fatalf("unexpected suites %+v", output)
+	}
+
+	cases := output.Suites[0].Cases
+	if cases[0].Name != "feed" || cases[0].Failure == nil || cases[1].Name != "1" || cases[1].Failure == nil {
+		t.Fatalf("unexpected cases %+v", cases)
+	}
+
+	if cases[2].Name != "2" || cases[2].Failure != nil || cases[2].SystemOut == "" {
+		t.Fatalf("warning case %+v", cases[2])
+	}
+}
+
+func TestWriteUnknownFormat(t *testing.T) {
+	if err := Write(&bytes.Buffer{}, "pdf", testReport()); err == nil {
+		t.Fatal("no error")
+	}
+
+	if _, err := ContentType("pdf"); err == nil {
+		t.Fatal("no error")
+	}
+}
@@ -0,0 +1,180 @@
+package report
+
+import (
+	"fmt"
+	"github.com/zfullio/price-placements/v2/validation"
+	"html/template"
+	"io"
+	"strings"
+)
+
+const maxThumbnails = 6
+
+const htmlTemplate = `<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body{font-family:-apple-system,"Segoe UI",Roboto,sans-serif;margin:24px;color:#222}
+h1{font-size:22px}h2{font-size:18px;margin-top:32px}
+table{border-collapse:collapse;width:100%}
+th,td{border:1px solid #ddd;padding:6px 8px;text-align:left;vertical-align:top;font-size:14px}
+th{background:#f5f5f5}
+.summary{display:flex;gap:32px;flex-wrap:wrap}
+.summary table{width:auto}
+.error{color:#b00020}.warning{color:#a86b00}
+.ok{color:#2e7d32}
+.thumbs img{width:64px;height:64px;object-fit:cover;margin:0 4px 4px 0;border:1px solid #ddd}
+.filters{margin:16px 0;display:flex;gap:12px;align-items:center}
+.filters input[type=search]{width:320px;padding:4px}
+ul.findings{margin:0;padding-left:18px}
+pre.source{font-size:12px;background:#fafafa;border:1px solid #ddd;padding:8px;overflow:auto;white-space:pre-wrap;word-break:break-all}
+pre.source:target{border-color:#f0c000;background:#fffbe6}
+pre.source .at{color:#777}
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Platform: {{.Platform}}. Generated: {{.Generated.Format "2006-01-02 15:04:05 MST"}}. Lots: {{len .Lots}}. Findings: {{len .Findings}}.</p>
+
+<div class="summary">
+<table>
+<tr><th>Severity</th><th>Count</th></tr>
+{{range .BySeverity}}<tr><td class="{{.Name}}">{{.Name}}</td><td>{{.Count}}</td></tr>
+{{else}}<tr><td colspan="2" class="ok">No findings</td></tr>
+{{end}}</table>
+<table>
+<tr><th>Rule</th><th>Count</th></tr>
+{{range .ByRule}}<tr><td>{{.Name}}</td><td>{{.Count}}</td></tr>
+{{end}}</table>
+</div>
+
+{{if .Other}}
+<h2>Feed findings</h2>
+<ul class="findings">
+{{range .Other}}{{template "finding" .}}{{end}}
+</ul>
+{{end}}
+
+<h2>Lots</h2>
+<div class="filters">
+<input type="search" id="search" placeholder="Search by ID, title or message">
+<select id="severity">
+<option value="">All lots</option>
+<option value="any">With findings</option>
+<option value="error">With errors</option>
+<option value="warning">With warnings</option>
+<option value="none">Without findings</option>
+</select>
+<span id="shown"></span>
+</div>
+<table id="lots">
+<thead><tr><th>#</th><th>ID</th><th>Title</th><th>Photos</th><th>Findings</th></tr></thead>
+<tbody>
+{{range $idx, $row := .Rows}}<tr data-severity="{{severities $row.Findings}}">
+<td>{{inc $idx}}</td>
+<td>{{$row.Lot.ID}}{{with $row.Lot.Position}}{{if .IsValid}}<br>{{template "line" .}}{{end}}{{end}}</td>
+<td>{{$row.Lot.Title}}</td>
+<td class="thumbs">{{range thumbnails $row.Lot.Images}}<a href="{{.}}" target="_blank" rel="noopener"><img src="{{.}}" loading="lazy" alt=""></a>{{end}}{{if gt (len $row.Lot.Images) 0}}<br>{{len $row.Lot.Images}} total{{end}}</td>
+<td>{{if $row.Findings}}<ul class="findings">{{range $row.Findings}}{{template "finding" .}}{{end}}</ul>{{else}}<span class="ok">OK</span>{{end}}</td>
+</tr>
+{{end}}</tbody>
+</table>
+
+{{if .Excerpts}}
+<h2>Source</h2>
+{{range .Excerpts}}<pre class="source" id="S{{.Offset}}"><span class="at">line {{.Line}}, column {{.Column}}</span>
+{{.Before}}<mark>{{.Tag}}</mark>{{.After}}</pre>
+{{end}}{{end}}
+
+<script>
+(function () {
+  var search = document.getElementById("search");
+  var severity = document.getElementById("severity");
+  var shown = document.getElementById("shown");
+  var rows = document.querySelectorAll("#lots tbody tr");
+
+  function apply() {
+    var text = search.value.toLowerCase();
+    var level = severity.value;
+    var count = 0;
+
+    rows.forEach(function (row) {
+      var severities = row.dataset.severity;
+      var visible = row.textContent.toLowerCase().indexOf(text) >= 0 &&
+        (level === "" ||
+          (level === "any" && severities !== "") ||
+          (level === "none" && severities === "") ||
+          severities.split(" ").indexOf(level) >= 0);
+
+      row.style.display = visible ? "" : "none";
+      if (visible) {
+        count++;
+      }
+    });
+
+    shown.textContent = count + " of " + rows.length + " lots";
+  }
+
+  search.addEventListener("input", apply);
+  severity.addEventListener("change", apply);
+  apply();
+})();
+</script>
+</body>
+</html>
+{{define "finding"}}<li class="{{.Severity}}">[{{.Rule}}] {{.Message}}{{if .Position.IsValid}} ({{template "line" .Position}}){{end}}</li>{{end}}
+{{define "line"}}{{if excerpted .}}<a href="#S{{.Offset}}">line {{.Line}}, column {{.Column}}</a>{{else}}line {{.Line}}, column {{.Column}}{{end}}{{end}}
+`
+
+type htmlView struct {
+	Report
+	Rows     []LotFindings
+	Other    []validation.Finding
+	Excerpts []excerpt
+}
+
+// RenderHTML writes the report as a self-contained HTML page. Instead of the whole source
+// the page embeds an excerpt around every finding and lot position, so it stays small for big feeds
+// and useful for minified ones.
+func RenderHTML(w io.Writer, report Report) error {
+	view := htmlView{Report: report}
+	view.Rows, view.Other = report.Group()
+	view.Excerpts = excerpts(report)
+
+	excerpted := make(map[int64]bool, len(view.Excerpts))
+	for _, item := range view.Excerpts {
+		excerpted[item.Offset] = true
+	}
+
+	tmpl, err := template.New("report").Funcs(template.FuncMap{
+		"inc":        func(idx int) int { return idx + 1 },
+		"excerpted":  func(position validation.Position) bool { return excerpted[position.Offset] },
+		"severities": severities,
+		"thumbnails": func(images []string) []string { return images[:min(len(images), maxThumbnails)] },
+	}).Parse(htmlTemplate)
+	if err != nil {
+		return fmt.Errorf("can't parse report template. Error:%w", err)
+	}
+
+	if err := tmpl.Execute(w, view); err != nil {
+		return fmt.Errorf("can't render report. Error:%w", err)
+	}
+
+	return nil
+}
+
+func severities(findings []validation.Finding) string {
+	seen := make(map[validation.Severity]bool)
+	result := make([]string, 0, 2)
+
+	for _, finding := range findings {
+		if !seen[finding.Severity] {
+			seen[finding.Severity] = true
+			result = append(result, string(finding.Severity))
+		}
+	}
+
+	return strings.Join(result, " ")
+}
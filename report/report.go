@@ -0,0 +1,110 @@
+package report
+
+import (
+	"github.com/zfullio/price-placements/v2/platform"
+	"github.com/zfullio/price-placements/v2/summary"
+	"github.com/zfullio/price-placements/v2/validation"
+	"sort"
+	"time"
+)
+
+// Report holds everything needed to render the validation results of a feed.
+type Report struct {
+	Title     string
+	Platform  string
+	Generated time.Time
+	Lots      []summary.Lot
+	Findings  []validation.Finding
+	// Source is the feed document, when set the report embeds excerpts of it around the findings and lots.
+	Source []byte
+}
+
+func FromFeed(title string, platformName string, feed platform.Feed, findings []validation.Finding) Report {
+	return Report{
+		Title:     title,
+		Platform:  platformName,
+		Generated: time.Now(),
+		Lots:      feed.Summaries(),
+		Findings:  findings,
+		Source:    feed.Raw(),
+	}
+}
+
+type Count struct {
+	Name  string
+	Count int
+}
+
+type LotFindings struct {
+	Lot      summary.Lot
+	Findings []validation.Finding
+}
+
+func (r Report) BySeverity() []Count {
+	return countBy(r.Findings, func(finding validation.Finding) string { return string(finding.Severity) })
+}
+
+func (r Report) ByRule() []Count {
+	return countBy(r.Findings, func(finding validation.Finding) string { return finding.Rule })
+}
+
+// Group assigns findings to lots by lot ID or, for findings without ID, by the lot start position.
+// Findings of the whole feed or of unknown lots are returned separately.
+func (r Report) Group() ([]LotFindings, []validation.Finding) {
+	lots := make([]LotFindings, len(r.Lots))
+	index := make(map[string]int, len(r.Lots))
+	positions := make(map[int64]int, len(r.Lots))
+
+	for idx, lot := range r.Lots {
+		lots[idx].Lot = lot
+
+		if _, ok := index[lot.ID]; !ok && lot.ID != "" {
+			index[lot.ID] = idx
+		}
+
+		if lot.Position.IsValid() {
+			positions[lot.Position.Offset] = idx
+		}
+	}
+
+	other := make([]validation.Finding, 0)
+
+	for _, finding := range r.Findings {
+		idx, ok := index[finding.ID]
+		if !ok && finding.ID == "" && finding.Position.IsValid() {
+			idx, ok = positions[finding.Position.Offset]
+		}
+
+		if !ok {
+			other = append(other, finding)
+
+			continue
+		}
+
+		lots[idx].Findings = append(lots[idx].Findings, finding)
+	}
+
+	return lots, other
+}
+
+func countBy(findings []validation.Finding, key func(validation.Finding) string) []Count {
+	counts := make(map[string]int)
+	for _, finding := range findings {
+		counts[key(finding)]++
+	}
+
+	result := make([]Count, 0, len(counts))
+	for name, count := range counts {
+		result = append(result, Count{Name: name, Count: count})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
@@ -0,0 +1,160 @@
+package schema
+
+import (
+	"bufio"
+	"fmt"
+	"strings"
+)
+
+const indentWidth = 2
+
+// Parse reads an element tree from its text description, one element per line:
+//
+//	# comment
+//	realty-feed required
+//	  offer multiple required
+//	    @internal-id required
+//	    creation-date date required
+//	    area
+//	      value decimal required
+//
+// Children are indented by two spaces more than their parent, attributes start with "@".
+// The words after the name are the value type (string, integer, decimal, boolean, date or any,
+// string by default for elements without children) and the flags required, multiple and open.
+// Open elements may contain any children, e.g. HTML markup.
+func Parse(text string) (*Element, error) {
+	var root *Element
+
+	stack := make([]*Element, 0)
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	number := 0
+
+	for scanner.Scan() {
+		number++
+
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		trimmed := strings.TrimLeft(line, " ")
+		indent := len(line) - len(trimmed)
+
+		if indent%indentWidth != 0 || indent/indentWidth > len(stack) {
+			return nil, fmt.Errorf("schema line %d: invalid indentation", number)
+		}
+
+		depth := indent / indentWidth
+		fields := strings.Fields(trimmed)
+		name := fields[0]
+
+		if strings.HasPrefix(name, "@") {
+			if depth == 0 {
+				return nil, fmt.Errorf("schema line %d: attribute %s has no element", number, name)
+			}
+
+			attr, err := parseAttr(strings.TrimPrefix(name, "@"), fields[1:])
+			if err != nil {
+				return nil, fmt.Errorf("schema line %d: %w", number, err)
+			}
+
+			parent := stack[depth-1]
+			parent.Attrs = append(parent.Attrs, attr)
+
+			continue
+		}
+
+		element, err := parseElement(name, fields[1:])
+		if err != nil {
+			return nil, fmt.Errorf("schema line %d: %w", number, err)
+		}
+
+		stack = stack[:depth]
+
+		switch {
+		case depth > 0:
+			parent := stack[depth-1]
+			if parent.Child(name) != nil {
+				return nil, fmt.Errorf("schema line %d: element %s is listed twice", number, name)
+			}
+
+			parent.Children = append(parent.Children, element)
+		case root != nil:
+			return nil, fmt.Errorf("schema line %d: second root element %s", number, name)
+		default:
+			root = element
+		}
+
+		stack = append(stack, element)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("schema has no root element")
+	}
+
+	root.Required = true
+
+	return root, nil
+}
+
+// MustParse is Parse for the schemas embedded in the platform packages, it panics on an error.
+func MustParse(text string) *Element {
+	root, err := Parse(text)
+	if err != nil {
+		panic(err)
+	}
+
+	return root
+}
+
+func parseElement(name string, words []string) (*Element, error) {
+	element := &Element{Name: name, Type: TypeString}
+
+	for _, word := range words {
+		switch word {
+		case "required":
+			element.Required = true
+		case "multiple":
+			element.Multiple = true
+		case "open":
+			element.Open = true
+		default:
+			t, ok := parseType(word)
+			if !ok {
+				return nil, fmt.Errorf("unknown word %q of element %s", word, name)
+			}
+
+			element.Type = t
+		}
+	}
+
+	return element, nil
+}
+
+func parseAttr(name string, words []string) (*Attr, error) {
+	attr := &Attr{Name: name}
+
+	for _, word := range words {
+		if word != "required" {
+			return nil, fmt.Errorf("unknown word %q of attribute %s", word, name)
+		}
+
+		attr.Required = true
+	}
+
+	return attr, nil
+}
+
+func parseType(word string) (Type, bool) {
+	for _, t := range []Type{TypeAny, TypeString, TypeInt, TypeFloat, TypeBool, TypeDate} {
+		if t.String() == word {
+			return t, true
+		}
+	}
+
+	return TypeAny, false
+}
@@ -0,0 +1,67 @@
+package schema
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	root, err := Parse(`
+# comment
+feed
+  @version required
+  offer multiple required  # trailing comment
+    @id
+    price decimal required
+    description open
+    area
+      value decimal
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if root.Name != "feed" || !root.Required || !root.Attr("version").Required {
+		t.Fatalf("unexpected root %+v", root)
+	}
+
+	offer := root.Child("offer")
+	if offer == nil || !offer.Multiple || !offer.Required || offer.Attr("id") == nil || offer.Attr("id").Required {
+		t.Fatalf("unexpected offer %+v", offer)
+	}
+
+	if price := offer.Child("price"); price == nil || price.Type != TypeFloat || !price.Required {
+		t.Fatalf("unexpected price %+v", price)
+	}
+
+	if description := offer.Child("description"); description == nil || !description.Open || description.Type != TypeString {
+		t.Fatalf("unexpected description %+v", description)
+	}
+
+	if value := offer.Child("area").Child("value"); value == nil || value.Type != TypeFloat {
+		t.Fatalf("unexpected area value %+v", value)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{name: "empty", text: "# nothing"},
+		{name: "odd indentation", text: "feed\n   offer"},
+		{name: "skipped level", text: "feed\n    offer"},
+		{name: "unknown word", text: "feed\n  offer mandatory"},
+		{name: "attribute type", text: "feed\n  @id integer"},
+		{name: "root attribute", text: "@id"},
+		{name: "two roots", text: "feed\nfeed2"},
+		{name: "duplicate", text: "feed\n  offer\n  offer"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.text); err == nil {
+				t.Fatal("no error")
+			}
+		})
+	}
+}
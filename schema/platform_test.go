@@ -0,0 +1,31 @@
+package schema_test
+
+import (
+	"github.com/zfullio/price-placements/v2/avito"
+	"github.com/zfullio/price-placements/v2/cian"
+	domclick "github.com/zfullio/price-placements/v2/dom_click"
+	"github.com/zfullio/price-placements/v2/realty"
+	"github.com/zfullio/price-placements/v2/schema"
+	"testing"
+)
+
+func TestPlatformSchemas(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema func() *schema.Element
+		root   string
+	}{
+		{name: "avito", schema: avito.Schema, root: "Ads"},
+		{name: "cian", schema: cian.Schema, root: "feed"},
+		{name: "realty", schema: realty.Schema, root: "realty-feed"},
+		{name: "domclick", schema: domclick.Schema, root: "complexes"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if root := tt.schema(); root.Name != tt.root {
+				t.Fatalf("root is %s, want %s", root.Name, tt.root)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package schema
+
+type Type int
+
+const (
+	TypeAny Type = iota
+	TypeString
+	TypeInt
+	TypeFloat
+	TypeBool
+	TypeDate
+)
+
+func (t Type) String() string {
+	switch t {
+	case TypeString:
+		return "string"
+	case TypeInt:
+		return "integer"
+	case TypeFloat:
+		return "decimal"
+	case TypeBool:
+		return "boolean"
+	case TypeDate:
+		return "date"
+	default:
+		return "any"
+	}
+}
+
+type Attr struct {
+	Name     string
+	Required bool
+}
+
+// Element describes an XML element of a platform feed: its value type, cardinality, attributes and children.
+type Element struct {
+	Name     string
+	Type     Type
+	Required bool
+	Multiple bool
+	// Open elements may contain children that are not described, e.g. HTML markup.
+	Open     bool
+	Attrs    []*Attr
+	Children []*Element
+}
+
+func (e *Element) Child(name string) *Element {
+	for _, child := range e.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+
+	return nil
+}
+
+func (e *Element) Attr(name string) *Attr {
+	for _, attr := range e.Attrs {
+		if attr.Name == name {
+			return attr
+		}
+	}
+
+	return nil
+}
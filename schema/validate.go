@@ -0,0 +1,206 @@
+package schema
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"github.com/zfullio/price-placements/v2/validation"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type frame struct {
+	element  *Element
+	path     string
+	position validation.Position
+	counts   map[string]int
+	text     strings.Builder
+}
+
+// Validate reads the XML document from r and checks it against the root element description.
+// Findings point to the position of the offending element in the source.
+func Validate(r io.Reader, root *Element) ([]validation.Finding, error) {
+	decoder := xml.NewDecoder(r)
+	index := indexNames(root, "", make(map[string][]string))
+	findings := make([]validation.Finding, 0)
+	stack := make([]*frame, 0)
+	seenRoot := false
+
+	for {
+		line, column := decoder.InputPos()
+		position := validation.Position{Offset: decoder.InputOffset(), Line: line, Column: column}
+
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return findings, fmt.Errorf("can't read feed structure. Error:%w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			current := &frame{path: t.Name.Local, position: position, counts: make(map[string]int)}
+
+			switch {
+			case len(stack) == 0:
+				seenRoot = true
+
+				if t.Name.Local != root.Name {
+					findings = append(findings, newFinding(validation.RuleStructureNesting, current,
+						fmt.Sprintf("root element is <%s>, expected <%s>", t.Name.Local, root.Name)))
+				} else {
+					current.element = root
+				}
+			case stack[len(stack)-1].element != nil && !stack[len(stack)-1].element.Open:
+				parent := stack[len(stack)-1]
+				current.path = parent.path + "/" + t.Name.Local
+				current.element = parent.element.Child(t.Name.Local)
+
+				if current.element == nil {
+					findings = append(findings, unexpected(current, t.Name.Local, parent, index))
+
+					break
+				}
+
+				parent.counts[t.Name.Local]++
+				if parent.counts[t.Name.Local] > 1 && !current.element.Multiple {
+					findings = append(findings, newFinding(validation.RuleStructureCardinality, current,
+						fmt.Sprintf("element <%s> occurs more than once in <%s>", t.Name.Local, parent.path)))
+				}
+
+				findings = append(findings, checkAttrs(current, t.Attr)...)
+			default:
+				current.path = stack[len(stack)-1].path + "/" + t.Name.Local
+			}
+
+			stack = append(stack, current)
+		case xml.CharData:
+			if len(stack) > 0 && stack[len(stack)-1].element != nil {
+				stack[len(stack)-1].text.Write(t)
+			}
+		case xml.EndElement:
+			current := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+			if current.element != nil {
+				findings = append(findings, checkElement(current)...)
+			}
+		}
+	}
+
+	if !seenRoot {
+		findings = append(findings, validation.Finding{
+			Severity: validation.SeverityError,
+			Rule:     validation.RuleStructureRequired,
+			Path:     root.Name,
+			Message:  fmt.Sprintf("root element <%s> is missing", root.Name),
+		})
+	}
+
+	return findings, nil
+}
+
+func checkAttrs(current *frame, attrs []xml.Attr) []validation.Finding {
+	findings := make([]validation.Finding, 0)
+
+	for _, attr := range current.element.Attrs {
+		if !attr.Required {
+			continue
+		}
+
+		found := false
+
+		for _, value := range attrs {
+			if value.Name.Local == attr.Name {
+				found = true
+
+				break
+			}
+		}
+
+		if !found {
+			findings = append(findings, newFinding(validation.RuleStructureRequired, current,
+				fmt.Sprintf("required attribute %s is missing in <%s>", attr.Name, current.path)))
+		}
+	}
+
+	return findings
+}
+
+func checkElement(current *frame) []validation.Finding {
+	findings := make([]validation.Finding, 0)
+
+	for _, child := range current.element.Children {
+		if child.Required && current.counts[child.Name] == 0 {
+			findings = append(findings, newFinding(validation.RuleStructureRequired, current,
+				fmt.Sprintf("required element <%s> is missing in <%s>", child.Name, current.path)))
+		}
+	}
+
+	text := strings.TrimSpace(current.text.String())
+	if text == "" || len(current.element.Children) > 0 || isValidValue(current.element.Type, text) {
+		return findings
+	}
+
+	return append(findings, newFinding(validation.RuleStructureType, current,
+		fmt.Sprintf("element <%s> has value '%s', expected %s", current.path, text, current.element.Type)))
+}
+
+func isValidValue(t Type, value string) bool {
+	var err error
+
+	switch t {
+	case TypeInt:
+		_, err = strconv.ParseInt(value, 10, 64)
+	case TypeFloat:
+		_, err = strconv.ParseFloat(value, 64)
+	case TypeBool:
+		_, ok := validation.ParseBool(value)
+
+		return ok
+	case TypeDate:
+		_, ok := validation.ParseDate(value)
+
+		return ok
+	case TypeAny, TypeString:
+	}
+
+	return err == nil
+}
+
+func unexpected(current *frame, name string, parent *frame, index map[string][]string) validation.Finding {
+	if expected, ok := index[name]; ok {
+		return newFinding(validation.RuleStructureNesting, current,
+			fmt.Sprintf("element <%s> is not allowed in <%s>, expected in %s", name, parent.path, strings.Join(expected, ", ")))
+	}
+
+	return newFinding(validation.RuleStructureUnknown, current,
+		fmt.Sprintf("unknown element <%s> in <%s>", name, parent.path))
+}
+
+func newFinding(rule string, current *frame, message string) validation.Finding {
+	return validation.Finding{
+		Severity: validation.RuleSeverity(rule),
+		Rule:     rule,
+		Path:     current.path,
+		Message:  message,
+		Position: current.position,
+	}
+}
+
+func indexNames(element *Element, parent string, index map[string][]string) map[string][]string {
+	path := element.Name
+	if parent != "" {
+		path = parent + "/" + element.Name
+	}
+
+	for _, child := range element.Children {
+		index[child.Name] = append(index[child.Name], "<"+path+">")
+		indexNames(child, path, index)
+	}
+
+	return index
+}
@@ -0,0 +1,75 @@
+package schema
+
+import (
+	"github.com/zfullio/price-placements/v2/validation"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	root := MustParse(`
+feed
+  offer multiple required
+    @id required
+    price decimal required
+    date date
+    new boolean
+    description open
+    area
+      value decimal
+`)
+
+	tests := []struct {
+		name  string
+		xml   string
+		rules []string
+	}{
+		{
+			name: "valid",
+			xml: `<feed><offer id="1"><price>10.5</price><date>2024-03-01</date><new>да</new>` +
+				`<description><p>Text</p></description><area><value>12</value></area></offer></feed>`,
+		},
+		{name: "root", xml: `<feeds></feeds>`, rules: []string{validation.RuleStructureNesting}},
+		{name: "missing offer", xml: `<feed></feed>`, rules: []string{validation.RuleStructureRequired}},
+		{name: "missing attribute", xml: `<feed><offer><price>1</price></offer></feed>`, rules: []string{validation.RuleStructureRequired}},
+		{name: "missing price", xml: `<feed><offer id="1"></offer></feed>`, rules: []string{validation.RuleStructureRequired}},
+		{name: "unknown", xml: `<feed><offer id="1"><price>1</price><color>red</color></offer></feed>`, rules: []string{validation.RuleStructureUnknown}},
+		{name: "nesting", xml: `<feed><offer id="1"><price>1</price><value>1</value></offer></feed>`, rules: []string{validation.RuleStructureNesting}},
+		{name: "cardinality", xml: `<feed><offer id="1"><price>1</price><price>2</price></offer></feed>`, rules: []string{validation.RuleStructureCardinality}},
+		{name: "decimal", xml: `<feed><offer id="1"><price>1 000</price></offer></feed>`, rules: []string{validation.RuleStructureType}},
+		{name: "date", xml: `<feed><offer id="1"><price>1</price><date>tomorrow</date></offer></feed>`, rules: []string{validation.RuleStructureType}},
+		{name: "boolean", xml: `<feed><offer id="1"><price>1</price><new>maybe</new></offer></feed>`, rules: []string{validation.RuleStructureType}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := Validate(strings.NewReader(tt.xml), root)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(findings) != len(tt.rules) {
+				t.Fatalf("got findings %v, want rules %v", findings, tt.rules)
+			}
+
+			for idx, finding := range findings {
+				if finding.Rule != tt.rules[idx] {
+					t.Fatalf("got findings %v, want rules %v", findings, tt.rules)
+				}
+			}
+		})
+	}
+}
+
+func TestValidatePosition(t *testing.T) {
+	root := MustParse("feed\n  offer multiple")
+
+	findings, err := Validate(strings.NewReader("<feed>\n  <offer/>\n  <lot/>\n</feed>"), root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(findings) != 1 || findings[0].Position.Line != 3 || findings[0].Position.Column != 3 {
+		t.Fatalf("unexpected findings %+v", findings)
+	}
+}
@@ -0,0 +1,480 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/zfullio/price-placements/v2/daemon"
+	"github.com/zfullio/price-placements/v2/decoding"
+	"github.com/zfullio/price-placements/v2/metrics"
+	"github.com/zfullio/price-placements/v2/option"
+	"github.com/zfullio/price-placements/v2/platform"
+	"github.com/zfullio/price-placements/v2/report"
+	"github.com/zfullio/price-placements/v2/summary"
+	"github.com/zfullio/price-placements/v2/transport"
+	"github.com/zfullio/price-placements/v2/validation"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxUpload   = 256 << 20
+	defaultReadTimeout = 30 * time.Second
+
+	uploadField = "file"
+
+	maxRedirects = 10
+)
+
+type ValidationResponse struct {
+	Platform     string               `json:"platform"`
+	URL          string               `json:"url,omitempty"`
+	File         string               `json:"file,omitempty"`
+	LastModified time.Time            `json:"last_modified"`
+	Lots         int                  `json:"lots"`
+	Stats        summary.Stats        `json:"stats"`
+	Findings     []validation.Finding `json:"findings"`
+}
+
+// ForbiddenURLError is returned when a feed URL given to the server is not allowed to be fetched.
+type ForbiddenURLError struct {
+	URL    string
+	Reason string
+}
+
+func (e *ForbiddenURLError) Error() string {
+	return fmt.Sprintf("feed url %s is not allowed: %s", e.URL, e.Reason)
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+	// Status is the response status of the feed source when it was not available.
+	Status   int                  `json:"status,omitempty"`
+	Position *validation.Position `json:"position,omitempty"`
+}
+
+// Server exposes feed validation and the status of monitored feeds over HTTP.
+//
+//	POST /api/validate/{platform}?url=...   validate a feed by URL
+//	POST /api/validate/{platform}           validate an uploaded feed, raw body or multipart field "file"
+//	GET  /api/feeds                         status of every monitored feed
+//	GET  /api/feeds/{name}                  status of a monitored feed
+//	POST /api/feeds/{name}/check            check a monitored feed now
+//	GET  /api/feeds/{name}/diff             changes between the two latest checks
+//	GET  /metrics                           Prometheus metrics when Metrics is set
+//
+// Validation accepts the query parameters lenient, structure and freshness set to true
+// and format set to json, csv, junit or html to get a report export instead of the response.
+//
+// Feeds validated by URL are fetched with MaxBodySize and ReadTimeout and only from public
+// addresses unless AllowPrivate is set.
+type Server struct {
+	Logger      *slog.Logger
+	client      *http.Client
+	daemon      *daemon.Daemon
+	MaxUpload   int64
+	MaxBodySize int64
+	ReadTimeout time.Duration
+	// AllowedHosts restricts the hosts of feeds validated by URL, also after redirects.
+	// An entry matches the host and its subdomains, empty allows any host.
+	AllowedHosts []string
+	AllowPrivate bool
+	Metrics      *metrics.Collector
+
+	fetchOnce   sync.Once
+	fetchClient *http.Client
+	fetchErr    error
+}
+
+// New returns a server. d may be nil when no feeds are monitored.
+func New(client *http.Client, d *daemon.Daemon) *Server {
+	return &Server{
+		Logger:      slog.Default(),
+		client:      client,
+		daemon:      d,
+		MaxUpload:   defaultMaxUpload,
+		MaxBodySize: defaultMaxUpload,
+		ReadTimeout: defaultReadTimeout,
+	}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/validate/", s.handleValidate)
+	mux.HandleFunc("/api/feeds", s.handleFeeds)
+	mux.HandleFunc("/api/feeds/", s.handleFeed)
+
+	if s.Metrics != nil {
+		mux.Handle("/metrics", s.Metrics)
+	}
+
+	return mux
+}
+
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		s.writeError(w, http.StatusMethodNotAllowed, errors.New("use POST"))
+
+		return
+	}
+
+	name := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/validate/"), "/")
+	query := r.URL.Query()
+	opts := []option.Option{
+		option.WithLogger(s.Logger),
+		option.WithMaxBodySize(s.MaxBodySize),
+		option.WithReadTimeout(s.ReadTimeout),
+	}
+
+	if isSet(query, "lenient") {
+		opts = append(opts, option.WithDecodeMode(decoding.Lenient))
+	}
+
+	client := s.client
+
+	if feedURL := query.Get("url"); feedURL != "" {
+		var err error
+
+		client, err = s.fetchClientFor(feedURL)
+		if err != nil {
+			s.writeLoadError(w, err)
+
+			return
+		}
+	}
+
+	feed, err := platform.New(name, client, query.Get("url"), opts...)
+	if err != nil {
+		s.writeError(w, http.StatusNotFound, err)
+
+		return
+	}
+
+	response := ValidationResponse{Platform: name, URL: query.Get("url")}
+
+	if response.URL != "" {
+		err = feed.Get(r.Context())
+	} else {
+		response.File, err = s.readUpload(w, r, feed)
+	}
+
+	if err != nil {
+		s.writeLoadError(w, err)
+
+		return
+	}
+
+	response.Findings, err = feed.Validate()
+	if err != nil {
+		s.writeError(w, http.StatusUnprocessableEntity, err)
+
+		return
+	}
+
+	if isSet(query, "structure") {
+		structure, err := feed.CheckStructure()
+		if err != nil {
+			s.writeError(w, http.StatusUnprocessableEntity, err)
+
+			return
+		}
+
+		response.Findings = append(response.Findings, structure...)
+	}
+
+	if isSet(query, "freshness") {
+		response.Findings = append(response.Findings, feed.CheckFreshness(time.Now())...)
+	}
+
+	response.LastModified, _ = feed.Dates().Resolve()
+	response.Lots = feed.Lots()
+	response.Stats = feed.Stats()
+
+	if format := query.Get("format"); format != "" {
+		contentType, err := report.ContentType(format)
+		if err != nil {
+			s.writeError(w, http.StatusBadRequest, err)
+
+			return
+		}
+
+		title := response.URL
+		if title == "" {
+			title = response.File
+		}
+
+		w.Header().Set("Content-Type", contentType)
+
+		if err := report.Write(w, format, report.FromFeed(title, name, feed, response.Findings)); err != nil {
+			s.Logger.ErrorContext(r.Context(), "can't write report", slog.String("format", format), slog.Any("error", err))
+		}
+
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, response)
+}
+
+// fetchClientFor checks the feed URL of a caller and returns the client that fetches it.
+func (s *Server) fetchClientFor(feedURL string) (*http.Client, error) {
+	parsed, err := url.Parse(feedURL)
+	if err != nil {
+		return nil, &ForbiddenURLError{URL: feedURL, Reason: err.Error()}
+	}
+
+	if parsed.Scheme != "http" && parsed.Scheme != "https" {
+		return nil, &ForbiddenURLError{URL: feedURL, Reason: "only http and https are supported"}
+	}
+
+	if !s.allowedHost(parsed.Hostname()) {
+		return nil, &ForbiddenURLError{URL: feedURL, Reason: "host is not allowed"}
+	}
+
+	s.fetchOnce.Do(func() {
+		client := http.Client{}
+		if s.client != nil {
+			client = *s.client
+		}
+
+		client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+
+			if !s.allowedHost(req.URL.Hostname()) {
+				return &ForbiddenURLError{URL: req.URL.String(), Reason: "redirect to a host that is not allowed"}
+			}
+
+			return nil
+		}
+
+		s.fetchClient = &client
+
+		if !s.AllowPrivate {
+			s.fetchClient, s.fetchErr = transport.PublicOnly(&client)
+		}
+	})
+
+	return s.fetchClient, s.fetchErr
+}
+
+func (s *Server) allowedHost(host string) bool {
+	if len(s.AllowedHosts) == 0 {
+		return true
+	}
+
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	for _, allowed := range s.AllowedHosts {
+		allowed = strings.ToLower(strings.TrimPrefix(allowed, "."))
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) readUpload(w http.ResponseWriter, r *http.Request, feed platform.Feed) (string, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, s.MaxUpload)
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		return "", feed.Read(r.Body)
+	}
+
+	file, header, err := r.FormFile(uploadField)
+	if err != nil {
+		return "", fmt.Errorf("can't read uploaded file. Error:%w", err)
+	}
+
+	defer file.Close()
+
+	return header.Filename, feed.Read(file)
+}
+
+func (s *Server) handleFeeds(w http.ResponseWriter, r *http.Request) {
+	if !s.monitored(w) {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		s.writeError(w, http.StatusMethodNotAllowed, errors.New("use GET"))
+
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, s.daemon.Status())
+}
+
+func (s *Server) handleFeed(w http.ResponseWriter, r *http.Request) {
+	if !s.monitored(w) {
+		return
+	}
+
+	name, action, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/feeds/"), "/"), "/")
+
+	status, ok := s.daemon.Latest(name)
+	if !ok {
+		s.writeError(w, http.StatusNotFound, fmt.Errorf("feed %s is not configured", name))
+
+		return
+	}
+
+	switch {
+	case action == "" && r.Method == http.MethodGet:
+		s.writeJSON(w, http.StatusOK, status)
+	case action == "check" && r.Method == http.MethodPost:
+		result, err := s.daemon.CheckNow(r.Context(), name)
+		if err != nil {
+			s.writeError(w, http.StatusConflict, err)
+
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, result)
+	case action == "diff" && r.Method == http.MethodGet:
+		diff, err := s.daemon.Diff(name)
+		if err != nil {
+			s.writeError(w, http.StatusNotFound, err)
+
+			return
+		}
+
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q",
+			fmt.Sprintf("%s-diff-%s.json", name, diff.To.UTC().Format("20060102T150405Z"))))
+		s.writeJSON(w, http.StatusOK, diff)
+	case action == "snapshots" && r.Method == http.MethodGet:
+		snapshots, err := s.daemon.Snapshots(name)
+		if err != nil {
+			s.writeError(w, http.StatusNotFound, err)
+
+			return
+		}
+
+		s.writeJSON(w, http.StatusOK, snapshots)
+	case action == "snapshot" && r.Method == http.MethodGet:
+		s.writeSnapshot(w, r, name)
+	default:
+		s.writeError(w, http.StatusNotFound, fmt.Errorf("unknown endpoint %s %s", r.Method, r.URL.Path))
+	}
+}
+
+// writeSnapshot sends the archived feed published at the "at" query parameter, now by default.
+func (s *Server) writeSnapshot(w http.ResponseWriter, r *http.Request, name string) {
+	moment := time.Now()
+
+	if at := r.URL.Query().Get("at"); at != "" {
+		parsed, ok := validation.ParseDate(at)
+		if !ok {
+			s.writeError(w, http.StatusBadRequest, fmt.Errorf("invalid at %q", at))
+
+			return
+		}
+
+		moment = parsed
+		if len(at) == len(time.DateOnly) {
+			moment = moment.Add(24*time.Hour - time.Nanosecond)
+		}
+	}
+
+	snapshot, body, err := s.daemon.Snapshot(name, moment)
+	if err != nil {
+		s.writeError(w, http.StatusNotFound, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q",
+		fmt.Sprintf("%s-%s.xml", name, snapshot.Fetched.UTC().Format("20060102T150405Z"))))
+	w.Header().Set("X-Feed-Fetched", snapshot.Fetched.Format(time.RFC3339))
+	w.Header().Set("X-Feed-Hash", snapshot.Hash)
+
+	if !snapshot.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", snapshot.LastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if _, err := w.Write(body); err != nil {
+		s.Logger.ErrorContext(r.Context(), "can't write snapshot", slog.Any("error", err))
+	}
+}
+
+func (s *Server) monitored(w http.ResponseWriter) bool {
+	if s.daemon == nil {
+		s.writeError(w, http.StatusNotFound, errors.New("no feeds are monitored"))
+
+		return false
+	}
+
+	return true
+}
+
+func isSet(query url.Values, name string) bool {
+	values, ok := query[name]
+	if !ok {
+		return false
+	}
+
+	if len(values) == 0 || values[0] == "" {
+		return true
+	}
+
+	value, err := strconv.ParseBool(values[0])
+
+	return err == nil && value
+}
+
+func (s *Server) writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(value); err != nil {
+		s.Logger.Error("can't write response", slog.Any("error", err))
+	}
+}
+
+func (s *Server) writeError(w http.ResponseWriter, status int, err error) {
+	s.writeJSON(w, status, errorResponse{Error: err.Error()})
+}
+
+// writeLoadError reports why the feed could not be fetched or decoded.
+func (s *Server) writeLoadError(w http.ResponseWriter, err error) {
+	var (
+		statusErr  *transport.HTTPStatusError
+		networkErr *transport.NetworkError
+		limitErr   *transport.LimitError
+		uploadErr  *http.MaxBytesError
+		decodeErr  *decoding.DecodeError
+		urlErr     *ForbiddenURLError
+		addressErr *transport.ForbiddenAddressError
+	)
+
+	response := errorResponse{Error: err.Error()}
+	status := http.StatusUnprocessableEntity
+
+	switch {
+	case errors.As(err, &urlErr), errors.As(err, &addressErr):
+		status = http.StatusForbidden
+	case errors.As(err, &statusErr):
+		status = http.StatusBadGateway
+		response.Status = statusErr.StatusCode
+	case errors.As(err, &limitErr), errors.As(err, &uploadErr):
+		status = http.StatusRequestEntityTooLarge
+	case errors.As(err, &networkErr):
+		status = http.StatusBadGateway
+	case errors.As(err, &decodeErr):
+		response.Position = &decodeErr.Position
+	}
+
+	s.writeJSON(w, status, response)
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestValidateURL(t *testing.T) {
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://example.org/feed.xml", http.StatusFound)
+
+			return
+		}
+
+		w.Write([]byte(`<Ads formatVersion="3" target="Avito.ru">` + strings.Repeat(" ", 1024) + `</Ads>`))
+	}))
+	defer feed.Close()
+
+	host, _, _ := strings.Cut(strings.TrimPrefix(feed.URL, "http://"), ":")
+
+	tests := []struct {
+		name   string
+		url    string
+		setup  func(s *Server)
+		status int
+	}{
+		{name: "loopback is forbidden", url: feed.URL, status: http.StatusForbidden},
+		{name: "scheme is forbidden", url: "file:///etc/passwd", setup: func(s *Server) { s.AllowPrivate = true }, status: http.StatusForbidden},
+		{
+			name:   "host is not allowed",
+			url:    feed.URL,
+			setup:  func(s *Server) { s.AllowPrivate, s.AllowedHosts = true, []string{"example.com"} },
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "redirect host is not allowed",
+			url:    feed.URL + "/redirect",
+			setup:  func(s *Server) { s.AllowPrivate, s.AllowedHosts = true, []string{host} },
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "body limit",
+			url:    feed.URL,
+			setup:  func(s *Server) { s.AllowPrivate, s.MaxBodySize = true, 512 },
+			status: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:   "allowed",
+			url:    feed.URL,
+			setup:  func(s *Server) { s.AllowPrivate, s.AllowedHosts = true, []string{host} },
+			status: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(feed.Client(), nil)
+			if tt.setup != nil {
+				tt.setup(s)
+			}
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/api/validate/avito?url="+url.QueryEscape(tt.url), nil)
+
+			s.Handler().ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.status {
+				t.Fatalf("status %v, want %v: %s", recorder.Code, tt.status, recorder.Body)
+			}
+
+			if tt.status == http.StatusOK {
+				return
+			}
+
+			var response errorResponse
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil || response.Error == "" {
+				t.Fatalf("invalid error response %s, %v", recorder.Body, err)
+			}
+		})
+	}
+}
+
+func TestValidateUpload(t *testing.T) {
+	feed := `<Ads formatVersion="3" target="Avito.ru">` + strings.Repeat(" ", 1024) + `</Ads>`
+
+	var form bytes.Buffer
+
+	writer := multipart.NewWriter(&form)
+	part, _ := writer.CreateFormFile(uploadField, "feed.xml")
+	part.Write([]byte(feed))
+	writer.Close()
+
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		maxUpload   int64
+		status      int
+	}{
+		{name: "body", body: feed, contentType: "application/xml", maxUpload: 4096, status: http.StatusOK},
+		{name: "oversize body", body: feed, contentType: "application/xml", maxUpload: 512, status: http.StatusRequestEntityTooLarge},
+		{name: "form", body: form.String(), contentType: writer.FormDataContentType(), maxUpload: 4096, status: http.StatusOK},
+		{name: "oversize form", body: form.String(), contentType: writer.FormDataContentType(), maxUpload: 512, status: http.StatusRequestEntityTooLarge},
+		{name: "malformed", body: "<Ads>", contentType: "application/xml", maxUpload: 4096, status: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(nil, nil)
+			s.MaxUpload = tt.maxUpload
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodPost, "/api/validate/avito", strings.NewReader(tt.body))
+			request.Header.Set("Content-Type", tt.contentType)
+
+			s.Handler().ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.status {
+				t.Fatalf("status %v, want %v: %s", recorder.Code, tt.status, recorder.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package summary
+
+import (
+	"fmt"
+	"strconv"
+)
+
+const (
+	RoomsStudio     = "studio"
+	RoomsFreeLayout = "free-layout"
+
+	// Unknown groups lots without the grouped value.
+	Unknown = "unknown"
+)
+
+// Offer holds the values of a lot counted by the feed statistics.
+type Offer struct {
+	// Rooms is the room count, RoomsStudio or RoomsFreeLayout, see Rooms.
+	Rooms      string
+	Building   string
+	Section    string
+	Decoration string
+	Price      float64
+	Area       float64
+	Photos     int
+	HasPlan    bool
+}
+
+// Range describes the values of the lots where the value is known.
+type Range struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Avg   float64 `json:"avg"`
+}
+
+func (r *Range) add(value float64) {
+	if r.Count == 0 || value < r.Min {
+		r.Min = value
+	}
+
+	if r.Count == 0 || value > r.Max {
+		r.Max = value
+	}
+
+	r.Avg += (value - r.Avg) / float64(r.Count+1)
+	r.Count++
+}
+
+// Stats are the lot counts of a feed.
+type Stats struct {
+	Lots          int            `json:"lots"`
+	ByRooms       map[string]int `json:"by_rooms"`
+	ByBuilding    map[string]int `json:"by_building"`
+	BySection     map[string]int `json:"by_section"`
+	ByDecoration  map[string]int `json:"by_decoration"`
+	ByPrice       map[string]int `json:"by_price"`
+	Price         Range          `json:"price"`
+	PricePerMeter Range          `json:"price_per_meter"`
+	// WithPhotos and WithPlans are shares of the lots, from 0 to 1.
+	WithPhotos float64 `json:"with_photos"`
+	WithPlans  float64 `json:"with_plans"`
+}
+
+// priceBounds are the upper bounds of the ByPrice groups in millions.
+func priceBounds() []float64 {
+	return []float64{3, 5, 7, 10, 15, 20, 30, 50}
+}
+
+// PriceGroup returns the ByPrice group of the price, e.g. "5-7M".
+func PriceGroup(price float64) string {
+	if price <= 0 {
+		return Unknown
+	}
+
+	lower := 0.0
+
+	for _, upper := range priceBounds() {
+		if price < upper*1e6 {
+			return fmt.Sprintf("%s-%sM", formatBound(lower), formatBound(upper))
+		}
+
+		lower = upper
+	}
+
+	return fmt.Sprintf("%sM+", formatBound(lower))
+}
+
+func formatBound(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// Rooms returns the Offer.Rooms of a positive room count and "" otherwise.
+func Rooms(count int64) string {
+	if count <= 0 {
+		return ""
+	}
+
+	return strconv.FormatInt(count, 10)
+}
+
+func Compute(offers []Offer) Stats {
+	stats := Stats{
+		Lots:         len(offers),
+		ByRooms:      make(map[string]int),
+		ByBuilding:   make(map[string]int),
+		BySection:    make(map[string]int),
+		ByDecoration: make(map[string]int),
+		ByPrice:      make(map[string]int),
+	}
+
+	photos, plans := 0, 0
+
+	for _, offer := range offers {
+		stats.ByRooms[group(offer.Rooms)]++
+		stats.ByBuilding[group(offer.Building)]++
+		stats.BySection[group(offer.Section)]++
+		stats.ByDecoration[group(offer.Decoration)]++
+		stats.ByPrice[PriceGroup(offer.Price)]++
+
+		if offer.Price > 0 {
+			stats.Price.add(offer.Price)
+
+			if offer.Area > 0 {
+				stats.PricePerMeter.add(offer.Price / offer.Area)
+			}
+		}
+
+		if offer.Photos > 0 {
+			photos++
+		}
+
+		if offer.HasPlan {
+			plans++
+		}
+	}
+
+	if len(offers) > 0 {
+		stats.WithPhotos = float64(photos) / float64(len(offers))
+		stats.WithPlans = float64(plans) / float64(len(offers))
+	}
+
+	return stats
+}
+
+func group(value string) string {
+	if value == "" {
+		return Unknown
+	}
+
+	return value
+}
@@ -0,0 +1,62 @@
+package summary
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPriceGroup(t *testing.T) {
+	tests := []struct {
+		price float64
+		want  string
+	}{
+		{price: 0, want: Unknown},
+		{price: -1, want: Unknown},
+		{price: 2_999_999, want: "0-3M"},
+		{price: 3_000_000, want: "3-5M"},
+		{price: 12_500_000, want: "10-15M"},
+		{price: 49_999_999, want: "30-50M"},
+		{price: 50_000_000, want: "50M+"},
+	}
+
+	for _, tt := range tests {
+		if got := PriceGroup(tt.price); got != tt.want {
+			t.Errorf("PriceGroup(%v) = %s, want %s", tt.price, got, tt.want)
+		}
+	}
+}
+
+func TestCompute(t *testing.T) {
+	stats := Compute([]Offer{
+		{Rooms: "1", Building: "A", Price: 4_000_000, Area: 40, Photos: 3, HasPlan: true},
+		{Rooms: "1", Building: "B", Price: 6_000_000, Area: 50},
+		{Rooms: RoomsStudio, Building: "A", Photos: 1},
+		{Rooms: Rooms(0), Price: 20_000_000},
+	})
+
+	if stats.Lots != 4 || stats.WithPhotos != 0.5 || stats.WithPlans != 0.25 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+
+	if want := map[string]int{"1": 2, RoomsStudio: 1, Unknown: 1}; !reflect.DeepEqual(stats.ByRooms, want) {
+		t.Fatalf("ByRooms = %v, want %v", stats.ByRooms, want)
+	}
+
+	if want := map[string]int{"3-5M": 1, "5-7M": 1, "20-30M": 1, Unknown: 1}; !reflect.DeepEqual(stats.ByPrice, want) {
+		t.Fatalf("ByPrice = %v, want %v", stats.ByPrice, want)
+	}
+
+	if want := (Range{Count: 3, Min: 4_000_000, Max: 20_000_000, Avg: 10_000_000}); stats.Price != want {
+		t.Fatalf("Price = %+v, want %+v", stats.Price, want)
+	}
+
+	if want := (Range{Count: 2, Min: 100_000, Max: 120_000, Avg: 110_000}); stats.PricePerMeter != want {
+		t.Fatalf("PricePerMeter = %+v, want %+v", stats.PricePerMeter, want)
+	}
+}
+
+func TestComputeEmpty(t *testing.T) {
+	if stats := Compute(nil); stats.Lots != 0 || stats.WithPhotos != 0 || stats.Price.Count != 0 {
+		t.Fatalf("unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,27 @@
+package summary
+
+import (
+	"github.com/zfullio/price-placements/v2/validation"
+	"strings"
+)
+
+// Lot is a platform independent description of a feed lot.
+type Lot struct {
+	ID       string              `json:"id"`
+	Title    string              `json:"title"`
+	Images   []string            `json:"images,omitempty"`
+	Position validation.Position `json:"position"`
+}
+
+// Title joins the non-empty parts of a lot description.
+func Title(parts ...string) string {
+	title := make([]string, 0, len(parts))
+
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			title = append(title, part)
+		}
+	}
+
+	return strings.Join(title, ", ")
+}
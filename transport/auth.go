@@ -0,0 +1,165 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// Auth prepares a request for an authenticated feed source.
+type Auth interface {
+	Authenticate(req *http.Request) error
+}
+
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+func (a BasicAuth) Authenticate(req *http.Request) error {
+	req.SetBasicAuth(a.Username, a.Password)
+
+	return nil
+}
+
+type BearerToken struct {
+	Token string
+}
+
+func (a BearerToken) Authenticate(req *http.Request) error {
+	if a.Token == "" {
+		return errors.New("bearer token is empty")
+	}
+
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+
+	return nil
+}
+
+// HeaderAuth sends a secret in a custom header, e.g. X-Api-Key.
+type HeaderAuth struct {
+	Name  string
+	Value string
+}
+
+func (a HeaderAuth) Authenticate(req *http.Request) error {
+	if a.Name == "" {
+		return errors.New("auth header name is empty")
+	}
+
+	req.Header.Set(a.Name, a.Value)
+
+	return nil
+}
+
+// QueryToken appends a token to the query string of the feed URL. The existing parameters are kept
+// as they are, so pre-signed URLs stay valid.
+type QueryToken struct {
+	Name  string
+	Value string
+}
+
+func (a QueryToken) Authenticate(req *http.Request) error {
+	if a.Name == "" {
+		return errors.New("query token name is empty")
+	}
+
+	param := url.QueryEscape(a.Name) + "=" + url.QueryEscape(a.Value)
+
+	if req.URL.RawQuery == "" {
+		req.URL.RawQuery = param
+	} else {
+		req.URL.RawQuery += "&" + param
+	}
+
+	return nil
+}
+
+func WithAuth(auth Auth) Option {
+	return func(t *Transport) {
+		t.auth = auth
+	}
+}
+
+func WithBasicAuth(username string, password string) Option {
+	return WithAuth(BasicAuth{Username: username, Password: password})
+}
+
+// WithTLSConfig uses config for the connections of the feed, e.g. with client certificates for mTLS.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(t *Transport) {
+		t.tlsConfig = config
+	}
+}
+
+// ClientCertificate loads a client certificate for mTLS. caFile is optional and replaces the system roots.
+func ClientCertificate(certFile string, keyFile string, caFile string) (*tls.Config, error) {
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("can't load client certificate. Error:%w", err)
+	}
+
+	config := &tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if caFile == "" {
+		return config, nil
+	}
+
+	ca, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("can't read CA certificate. Error:%w", err)
+	}
+
+	config.RootCAs = x509.NewCertPool()
+	if !config.RootCAs.AppendCertsFromPEM(ca) {
+		return nil, fmt.Errorf("can't parse CA certificate %s", caFile)
+	}
+
+	return config, nil
+}
+
+// Wrapper is implemented by round trippers decorating another one, so TLS settings reach the inner transport.
+type Wrapper interface {
+	Unwrap() http.RoundTripper
+	Wrap(next http.RoundTripper) http.RoundTripper
+}
+
+func withTLS(roundTripper http.RoundTripper, config *tls.Config) (http.RoundTripper, error) {
+	next, err := configure(roundTripper, func(base *http.Transport) {
+		base.TLSClientConfig = config
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't set TLS config. Error:%w", err)
+	}
+
+	return next, nil
+}
+
+// configure applies change to a clone of the *http.Transport behind roundTripper.
+func configure(roundTripper http.RoundTripper, change func(*http.Transport)) (http.RoundTripper, error) {
+	switch base := roundTripper.(type) {
+	case nil:
+		return configure(http.DefaultTransport, change)
+	case *http.Transport:
+		clone := base.Clone()
+		change(clone)
+
+		return clone, nil
+	case Wrapper:
+		next, err := configure(base.Unwrap(), change)
+		if err != nil {
+			return nil, err
+		}
+
+		return base.Wrap(next), nil
+	default:
+		return nil, fmt.Errorf("unsupported transport %T", roundTripper)
+	}
+}
@@ -0,0 +1,62 @@
+package transport
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestAuthenticate(t *testing.T) {
+	tests := []struct {
+		name   string
+		auth   Auth
+		url    string
+		header string
+		value  string
+		query  string
+	}{
+		{name: "basic", auth: BasicAuth{Username: "user", Password: "secret"}, url: "https://example.com/feed.xml",
+			header: "Authorization", value: "Basic dXNlcjpzZWNyZXQ="},
+		{name: "bearer", auth: BearerToken{Token: "token"}, url: "https://example.com/feed.xml",
+			header: "Authorization", value: "Bearer token"},
+		{name: "header", auth: HeaderAuth{Name: "X-Api-Key", Value: "key"}, url: "https://example.com/feed.xml",
+			header: "X-Api-Key", value: "key"},
+		{name: "query", auth: QueryToken{Name: "token", Value: "a b&c"}, url: "https://example.com/feed.xml",
+			query: "token=a+b%26c"},
+		{
+			name:  "query keeps signed parameters",
+			auth:  QueryToken{Name: "token", Value: "t"},
+			url:   "https://example.com/feed.xml?X-Amz-Signature=ab%2Fcd&X-Amz-Date=20240101&a=1",
+			query: "X-Amz-Signature=ab%2Fcd&X-Amz-Date=20240101&a=1&token=t",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := tt.auth.Authenticate(req); err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.header != "" && req.Header.Get(tt.header) != tt.value {
+				t.Errorf("header %s = %q, want %q", tt.header, req.Header.Get(tt.header), tt.value)
+			}
+
+			if tt.query != "" && req.URL.RawQuery != tt.query {
+				t.Errorf("query = %q, want %q", req.URL.RawQuery, tt.query)
+			}
+		})
+	}
+}
+
+func TestAuthenticateInvalid(t *testing.T) {
+	for _, auth := range []Auth{BearerToken{}, HeaderAuth{Value: "key"}, QueryToken{Value: "t"}} {
+		req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+		if err := auth.Authenticate(req); err == nil {
+			t.Errorf("%T has no error", auth)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// HTTPStatusError is returned when the feed source responds with a status other than 200 OK.
+type HTTPStatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+	Header     http.Header
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("feed not available. Status:%s", e.Status)
+}
+
+// NetworkError is returned when the feed can't be requested or its body can't be read,
+// e.g. on DNS, connection or TLS failures and timeouts.
+type NetworkError struct {
+	Method string
+	URL    string
+	Err    error
+}
+
+func (e *NetworkError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// NotFetchedError is returned when a feed is checked before it was fetched or read.
+type NotFetchedError struct {
+	URL string
+}
+
+func (e *NotFetchedError) Error() string {
+	return "feed not got"
+}
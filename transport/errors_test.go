@@ -0,0 +1,119 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTypedErrors(t *testing.T) {
+	closed := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	closed.Close()
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		url     string
+		opts    []Option
+		check   func(t *testing.T, err error)
+	}{
+		{
+			name: "status",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			check: func(t *testing.T, err error) {
+				var statusErr *HTTPStatusError
+				if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound || statusErr.Method != http.MethodGet {
+					t.Errorf("got %v, want a 404 HTTPStatusError", err)
+				}
+			},
+		},
+		{
+			name: "network",
+			url:  closed.URL,
+			check: func(t *testing.T, err error) {
+				var networkErr *NetworkError
+				if !errors.As(err, &networkErr) || networkErr.URL != closed.URL {
+					t.Errorf("got %v, want a NetworkError", err)
+				}
+			},
+		},
+		{
+			name: "announced size",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.Write([]byte(strings.Repeat("x", 100)))
+			},
+			opts: []Option{WithMaxBodySize(10)},
+			check: func(t *testing.T, err error) {
+				var limitErr *LimitError
+				if !errors.As(err, &limitErr) || limitErr.Limit != 10 || limitErr.ContentLength != 100 {
+					t.Errorf("got %v, want a LimitError of 100 bytes", err)
+				}
+			},
+		},
+		{
+			name: "read size",
+			handler: func(w http.ResponseWriter, _ *http.Request) {
+				w.(http.Flusher).Flush()
+				w.Write([]byte(strings.Repeat("x", 100)))
+			},
+			opts: []Option{WithMaxBodySize(10)},
+			check: func(t *testing.T, err error) {
+				var limitErr *LimitError
+				if !errors.As(err, &limitErr) || limitErr.ContentLength != -1 {
+					t.Errorf("got %v, want a LimitError while reading", err)
+				}
+			},
+		},
+		{
+			name: "read timeout",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.(http.Flusher).Flush()
+				<-r.Context().Done()
+			},
+			opts: []Option{WithReadTimeout(50 * time.Millisecond)},
+			check: func(t *testing.T, err error) {
+				var timeoutErr *ReadTimeoutError
+				if !errors.As(err, &timeoutErr) || timeoutErr.Timeout != 50*time.Millisecond {
+					t.Errorf("got %v, want a ReadTimeoutError", err)
+				}
+
+				var networkErr *NetworkError
+				if !errors.As(err, &networkErr) {
+					t.Errorf("got %v, want a NetworkError", err)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url := tt.url
+			if tt.handler != nil {
+				server := httptest.NewServer(tt.handler)
+				defer server.Close()
+
+				url = server.URL
+			}
+
+			tt.check(t, fetch(New(nil, tt.opts...), url))
+		})
+	}
+}
+
+func fetch(transport *Transport, url string) error {
+	response, err := transport.Get(context.Background(), url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	_, err = transport.ReadBody(response)
+
+	return err
+}
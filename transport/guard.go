@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ForbiddenAddressError is returned when a feed host resolves to an address rejected by PublicOnly.
+type ForbiddenAddressError struct {
+	Address string
+}
+
+func (e *ForbiddenAddressError) Error() string {
+	return fmt.Sprintf("address %s is not public", e.Address)
+}
+
+// PublicOnly returns a copy of the client that refuses to connect to loopback, private, link-local
+// and other non-public addresses, so feed URLs of untrusted callers can't reach internal services.
+// The address is checked on every dial after DNS resolution, which covers redirects as well.
+func PublicOnly(client *http.Client) (*http.Client, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	clone := *client
+
+	var err error
+
+	clone.Transport, err = configure(clone.Transport, func(base *http.Transport) {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Control: publicControl}
+
+		base.DialContext = dialer.DialContext
+		// A proxy would connect on our behalf, bypassing the check.
+		base.Proxy = nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("can't restrict client to public addresses. Error:%w", err)
+	}
+
+	return &clone, nil
+}
+
+func publicControl(_ string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+
+	if !IsPublic(addr) {
+		return &ForbiddenAddressError{Address: host}
+	}
+
+	return nil
+}
+
+// IsPublic reports whether the address is routable on the internet.
+func IsPublic(addr netip.Addr) bool {
+	addr = addr.Unmap()
+
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+
+	for _, prefix := range reservedPrefixes() {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// reservedPrefixes are the non-public ranges netip.Addr methods don't cover.
+func reservedPrefixes() []netip.Prefix {
+	return []netip.Prefix{
+		netip.MustParsePrefix("0.0.0.0/8"),
+		netip.MustParsePrefix("100.64.0.0/10"),
+		netip.MustParsePrefix("192.0.0.0/24"),
+		netip.MustParsePrefix("198.18.0.0/15"),
+		netip.MustParsePrefix("240.0.0.0/4"),
+		netip.MustParsePrefix("64:ff9b::/96"),
+	}
+}
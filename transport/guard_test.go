@@ -0,0 +1,57 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+)
+
+func TestIsPublic(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{addr: "8.8.8.8", want: true},
+		{addr: "2a00:1450:4010:c05::8b", want: true},
+		{addr: "127.0.0.1"},
+		{addr: "::1"},
+		{addr: "10.1.2.3"},
+		{addr: "172.16.0.1"},
+		{addr: "192.168.1.1"},
+		{addr: "169.254.169.254"},
+		{addr: "100.64.0.1"},
+		{addr: "0.0.0.0"},
+		{addr: "::"},
+		{addr: "fd00::1"},
+		{addr: "fe80::1"},
+		{addr: "::ffff:127.0.0.1"},
+		{addr: "64:ff9b::7f00:1"},
+		{addr: "255.255.255.255"},
+	}
+
+	for _, tt := range tests {
+		if got := IsPublic(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("IsPublic(%s) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestPublicOnly(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client, err := PublicOnly(server.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = New(client).Get(context.Background(), server.URL)
+
+	var forbidden *ForbiddenAddressError
+	if !errors.As(err, &forbidden) {
+		t.Fatalf("got error %v, want ForbiddenAddressError", err)
+	}
+}
@@ -0,0 +1,202 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// LimitError is returned when a feed body is larger than the configured maximum size.
+type LimitError struct {
+	Limit int64
+	// ContentLength is the announced size, -1 when the limit was hit while reading.
+	ContentLength int64
+}
+
+func (e *LimitError) Error() string {
+	if e.ContentLength >= 0 {
+		return fmt.Sprintf("feed size %d bytes exceeds the limit of %d bytes", e.ContentLength, e.Limit)
+	}
+
+	return fmt.Sprintf("feed exceeds the limit of %d bytes", e.Limit)
+}
+
+// ReadTimeoutError is returned when the server sends nothing for longer than the read timeout.
+type ReadTimeoutError struct {
+	Timeout time.Duration
+}
+
+func (e *ReadTimeoutError) Error() string {
+	return fmt.Sprintf("no data received for %s", e.Timeout)
+}
+
+// Progress reports the bytes read so far and the Content-Length, which is -1 when unknown.
+type Progress func(read int64, total int64)
+
+// WithReadTimeout aborts a request when no data arrives for the timeout, however long the whole download takes.
+func WithReadTimeout(timeout time.Duration) Option {
+	return func(t *Transport) {
+		t.readTimeout = timeout
+	}
+}
+
+func WithProgress(progress Progress) Option {
+	return func(t *Transport) {
+		t.progress = progress
+	}
+}
+
+// ReadBody reads the response body up to the configured maximum size, reporting the progress.
+func (t *Transport) ReadBody(response *http.Response) ([]byte, error) {
+	limit := t.maxBodySize
+	if limit > 0 && response.ContentLength > limit {
+		return nil, &LimitError{Limit: limit, ContentLength: response.ContentLength}
+	}
+
+	var body io.Reader = response.Body
+	if t.progress != nil {
+		body = &progressReader{reader: body, total: response.ContentLength, progress: t.progress}
+	}
+
+	if limit > 0 {
+		body = io.LimitReader(body, limit+1)
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		method, url := requestTarget(response)
+
+		return data, &NetworkError{Method: method, URL: url, Err: err}
+	}
+
+	if limit > 0 && int64(len(data)) > limit {
+		return nil, &LimitError{Limit: limit, ContentLength: -1}
+	}
+
+	return data, nil
+}
+
+// requestTarget returns the method and URL of the response request without credentials and query tokens.
+func requestTarget(response *http.Response) (string, string) {
+	if response.Request == nil || response.Request.URL == nil {
+		return "", ""
+	}
+
+	return response.Request.Method, redact(response.Request.URL)
+}
+
+// redact returns the URL without user info and query, where credentials and auth tokens are passed.
+func redact(target *url.URL) string {
+	redacted := *target
+	redacted.User = nil
+	redacted.RawQuery = ""
+	redacted.ForceQuery = false
+
+	return redacted.String()
+}
+
+// redactError replaces the URL of a client error, which is the full request URL, with target.
+func redactError(err error, target string) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = target
+	}
+
+	return err
+}
+
+type progressReader struct {
+	reader   io.Reader
+	read     int64
+	total    int64
+	progress Progress
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.read += int64(n)
+		r.progress(r.read, r.total)
+	}
+
+	return n, err
+}
+
+// deadline holds the request and read timeouts of a single request.
+type deadline struct {
+	cancel  context.CancelCauseFunc
+	stop    context.CancelFunc
+	timer   *time.Timer
+	timeout time.Duration
+}
+
+func (t *Transport) deadline(ctx context.Context) (context.Context, *deadline) {
+	d := &deadline{stop: func() {}, timeout: t.readTimeout}
+
+	if t.timeout > 0 {
+		ctx, d.stop = context.WithTimeout(ctx, t.timeout)
+	}
+
+	ctx, d.cancel = context.WithCancelCause(ctx)
+
+	if t.readTimeout > 0 {
+		d.timer = time.AfterFunc(t.readTimeout, func() {
+			d.cancel(&ReadTimeoutError{Timeout: t.readTimeout})
+		})
+	}
+
+	return ctx, d
+}
+
+func (d *deadline) extend() {
+	if d.timer != nil {
+		d.timer.Reset(d.timeout)
+	}
+}
+
+func (d *deadline) release() {
+	if d.timer != nil {
+		d.timer.Stop()
+	}
+
+	d.cancel(nil)
+	d.stop()
+}
+
+// cause replaces the context error of a request aborted by the read timeout.
+func (d *deadline) cause(ctx context.Context, err error) error {
+	var timeoutErr *ReadTimeoutError
+	if err != nil && errors.As(context.Cause(ctx), &timeoutErr) {
+		return timeoutErr
+	}
+
+	return err
+}
+
+// deadlineBody extends the read timeout on every read and releases the request once closed.
+type deadlineBody struct {
+	io.ReadCloser
+	ctx      context.Context
+	deadline *deadline
+}
+
+func (b *deadlineBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if errors.Is(err, io.EOF) {
+		return n, err
+	}
+
+	b.deadline.extend()
+
+	return n, b.deadline.cause(b.ctx, err)
+}
+
+func (b *deadlineBody) Close() error {
+	defer b.deadline.release()
+
+	return b.ReadCloser.Close()
+}
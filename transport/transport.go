@@ -2,46 +2,187 @@ package transport
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
+	"io"
+	"log/slog"
+	"math"
 	"net/http"
+	"time"
 )
 
 const (
 	HeaderLastModified = "Last-Modified"
 )
 
-func GetResponse(ctx context.Context, cl *http.Client, url string) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("can't get feed. Error:%w", err)
+type Option func(*Transport)
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(t *Transport) {
+		if logger != nil {
+			t.logger = logger
+		}
 	}
+}
 
-	response, err := cl.Do(req)
-	if err != nil {
-		return response, fmt.Errorf("can't get feed. Error:%w", err)
+// WithHeaders adds the headers to every request, values of the same key are merged.
+func WithHeaders(header http.Header) Option {
+	return func(t *Transport) {
+		for key, values := range header {
+			for _, value := range values {
+				t.header.Add(key, value)
+			}
+		}
 	}
+}
 
-	if response.StatusCode != 200 {
-		return response, fmt.Errorf("feed not availible. Status:%s", response.Status)
+func WithUserAgent(userAgent string) Option {
+	return func(t *Transport) {
+		t.header.Set("User-Agent", userAgent)
 	}
+}
 
-	return response, err
+// WithTimeout limits a request including the body download, independently of the client timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(t *Transport) {
+		t.timeout = timeout
+	}
 }
 
-func GetOnlyHeader(ctx context.Context, cl *http.Client, url string) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+// WithMaxBodySize limits the bytes read by ReadBody, zero means no limit.
+func WithMaxBodySize(size int64) Option {
+	return func(t *Transport) {
+		t.maxBodySize = size
+	}
+}
+
+// Transport performs feed requests with the configured client and options.
+type Transport struct {
+	client      *http.Client
+	logger      *slog.Logger
+	header      http.Header
+	auth        Auth
+	tlsConfig   *tls.Config
+	timeout     time.Duration
+	readTimeout time.Duration
+	maxBodySize int64
+	progress    Progress
+	err         error
+}
+
+// DiscardLogger returns a logger that drops every record, the default logger of the feeds:
+// their errors are returned, the records are only diagnostics for callers that pass a logger.
+func DiscardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.Level(math.MaxInt)}))
+}
+
+func New(client *http.Client, opts ...Option) *Transport {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	t := &Transport{
+		client: client,
+		logger: DiscardLogger(),
+		header: make(http.Header),
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	if t.tlsConfig != nil {
+		clone := *t.client
+
+		clone.Transport, t.err = withTLS(clone.Transport, t.tlsConfig)
+		t.client = &clone
+	}
+
+	return t
+}
+
+func (t *Transport) Logger() *slog.Logger {
+	return t.logger
+}
+
+func (t *Transport) Get(ctx context.Context, url string) (*http.Response, error) {
+	return t.do(ctx, http.MethodGet, url)
+}
+
+func (t *Transport) Head(ctx context.Context, url string) (*http.Response, error) {
+	return t.do(ctx, http.MethodHead, url)
+}
+
+func (t *Transport) do(ctx context.Context, method string, url string) (*http.Response, error) {
+	action := "get feed"
+	if method == http.MethodHead {
+		action = "get feed info"
+	}
+
+	if t.err != nil {
+		return nil, fmt.Errorf("can't %s. Error:%w", action, t.err)
+	}
+
+	ctx, deadline := t.deadline(ctx)
+
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("can't get feed info. Error:%w", err)
+		deadline.release()
+
+		return nil, fmt.Errorf("can't %s. Error:%w", action, err)
+	}
+
+	for key, values := range t.header {
+		req.Header[key] = values
 	}
 
-	response, err := cl.Do(req)
+	if t.auth != nil {
+		if err := t.auth.Authenticate(req); err != nil {
+			deadline.release()
+
+			return nil, fmt.Errorf("can't authenticate request. Error:%w", err)
+		}
+	}
+
+	started := time.Now()
+	target := redact(req.URL)
+
+	response, err := t.client.Do(req)
 	if err != nil {
-		return response, fmt.Errorf("can't get feed info. Error:%w", err)
+		err = redactError(deadline.cause(ctx, err), target)
+		deadline.release()
+		t.logger.DebugContext(ctx, "feed request failed",
+			slog.String("method", method), slog.String("url", target),
+			slog.Duration("duration", time.Since(started)), slog.Any("error", err))
+
+		return response, fmt.Errorf("can't %s. Error:%w", action, &NetworkError{Method: method, URL: target, Err: err})
 	}
 
+	t.logger.DebugContext(ctx, "feed response",
+		slog.String("method", method), slog.String("url", target), slog.Int("status", response.StatusCode),
+		slog.Int64("content_length", response.ContentLength), slog.Duration("duration", time.Since(started)))
+
+	response.Body = &deadlineBody{ReadCloser: response.Body, ctx: ctx, deadline: deadline}
+
 	if response.StatusCode != 200 {
-		return response, fmt.Errorf("feed not availible. Status:%s", response.Status)
+		response.Body.Close()
+
+		return response, &HTTPStatusError{
+			Method:     method,
+			URL:        target,
+			StatusCode: response.StatusCode,
+			Status:     response.Status,
+			Header:     response.Header,
+		}
 	}
 
-	return response, err
+	return response, nil
+}
+
+func GetResponse(ctx context.Context, cl *http.Client, url string) (*http.Response, error) {
+	return New(cl).Get(ctx, url)
+}
+
+func GetOnlyHeader(ctx context.Context, cl *http.Client, url string) (*http.Response, error) {
+	return New(cl).Head(ctx, url)
 }
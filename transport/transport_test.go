@@ -0,0 +1,95 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDefaultLoggerDiscards(t *testing.T) {
+	for _, level := range []slog.Level{slog.LevelDebug, slog.LevelWarn, slog.LevelError} {
+		if New(nil).Logger().Enabled(context.Background(), level) {
+			t.Fatalf("default logger is enabled at %s", level)
+		}
+	}
+}
+
+func TestFailureLoggedAtDebug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	var buffer bytes.Buffer
+
+	logger := slog.New(slog.NewTextHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	if _, err := New(nil, WithLogger(logger)).Get(context.Background(), url); err == nil {
+		t.Fatal("no error")
+	}
+
+	if buffer.Len() != 0 {
+		t.Fatalf("failure logged above debug: %s", buffer.String())
+	}
+
+	buffer.Reset()
+
+	logger = slog.New(slog.NewTextHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	if _, err := New(nil, WithLogger(logger)).Get(context.Background(), url); err == nil {
+		t.Fatal("no error")
+	}
+
+	if !strings.Contains(buffer.String(), "feed request failed") {
+		t.Fatalf("failure not logged: %s", buffer.String())
+	}
+}
+
+func TestErrorsHideQueryToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	closed := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	closed.Close()
+
+	for _, target := range []string{server.URL, closed.URL} {
+		var buffer bytes.Buffer
+
+		logger := slog.New(slog.NewTextHandler(&buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+		transport := New(nil, WithLogger(logger), WithAuth(QueryToken{Name: "token", Value: "SECRET123"}))
+
+		_, err := transport.Get(context.Background(), target+"/feed.xml?id=7")
+		if err == nil {
+			t.Fatal("no error")
+		}
+
+		var networkErr *NetworkError
+
+		var statusErr *HTTPStatusError
+
+		switch {
+		case errors.As(err, &networkErr):
+			if strings.Contains(networkErr.URL, "SECRET123") {
+				t.Fatalf("network error URL %s contains the token", networkErr.URL)
+			}
+		case errors.As(err, &statusErr):
+			if strings.Contains(statusErr.URL, "SECRET123") {
+				t.Fatalf("status error URL %s contains the token", statusErr.URL)
+			}
+		default:
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if strings.Contains(err.Error(), "SECRET123") {
+			t.Fatalf("error %q contains the token", err)
+		}
+
+		if strings.Contains(buffer.String(), "SECRET123") {
+			t.Fatalf("log contains the token: %s", buffer.String())
+		}
+	}
+}
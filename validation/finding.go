@@ -0,0 +1,165 @@
+package validation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+const (
+	RuleFeedSize    = "feed-size"
+	RuleEmpty       = "empty"
+	RuleMissing     = "missing"
+	RuleZero        = "zero"
+	RuleEnum        = "enum"
+	RuleFormat      = "format"
+	RuleArea        = "area"
+	RuleConsistency = "consistency"
+	RuleRooms       = "rooms"
+	RuleImages      = "images"
+	RuleDeadline    = "deadline"
+	RuleDecode      = "decode"
+	RuleStale       = "stale"
+	RuleUnchanged   = "unchanged"
+	RuleFutureDate  = "future-date"
+	RuleOutdated    = "outdated"
+
+	RuleFreshnessUnknown = "freshness-unknown"
+
+	RuleStructureUnknown     = "structure-unknown"
+	RuleStructureNesting     = "structure-nesting"
+	RuleStructureRequired    = "structure-required"
+	RuleStructureCardinality = "structure-cardinality"
+	RuleStructureType        = "structure-type"
+)
+
+func RuleSeverity(rule string) Severity {
+	switch rule {
+	case RuleArea, RuleImages, RuleDeadline, RuleStructureUnknown, RuleFreshnessUnknown,
+		RuleUnchanged, RuleOutdated:
+		return SeverityWarning
+	default:
+		return SeverityError
+	}
+}
+
+type Position struct {
+	Offset int64 `json:"offset"`
+	Line   int   `json:"line"`
+	Column int   `json:"column"`
+}
+
+func (p Position) IsValid() bool {
+	return p.Line > 0
+}
+
+type Finding struct {
+	Severity Severity `json:"severity"`
+	Rule     string   `json:"rule"`
+	Path     string   `json:"path"`
+	ID       string   `json:"id,omitempty"`
+	Message  string   `json:"message"`
+	Position Position `json:"position"`
+}
+
+func (f Finding) String() string {
+	var builder strings.Builder
+
+	builder.WriteString(f.Message)
+
+	if f.ID != "" {
+		builder.WriteString(fmt.Sprintf(". InternalID: %s", f.ID))
+	}
+
+	if f.Position.IsValid() {
+		builder.WriteString(fmt.Sprintf(". Line: %d, Column: %d", f.Position.Line, f.Position.Column))
+	}
+
+	return builder.String()
+}
+
+func Strings(findings []Finding) []string {
+	results := make([]string, 0, len(findings))
+	for _, finding := range findings {
+		results = append(results, finding.String())
+	}
+
+	return results
+}
+
+func Add(results *[]Finding, rule string, ID string, path string, message string) {
+	*results = append(*results, Finding{
+		Severity: RuleSeverity(rule),
+		Rule:     rule,
+		Path:     path,
+		ID:       ID,
+		Message:  message,
+	})
+}
+
+func SetPosition(findings []Finding, position Position) {
+	for idx := range findings {
+		if !findings[idx].Position.IsValid() {
+			findings[idx].Position = position
+		}
+	}
+}
+
+// Difference returns the findings of source absent in other. Positions are ignored
+// because lots move around between feed generations.
+func Difference(source []Finding, other []Finding) []Finding {
+	seen := make(map[string]int, len(other))
+	for _, finding := range other {
+		seen[finding.key()]++
+	}
+
+	missing := make([]Finding, 0)
+
+	for _, finding := range source {
+		key := finding.key()
+		if seen[key] > 0 {
+			seen[key]--
+
+			continue
+		}
+
+		missing = append(missing, finding)
+	}
+
+	return missing
+}
+
+func (f Finding) key() string {
+	return f.Rule + "\x00" + f.ID + "\x00" + f.Path + "\x00" + f.Message
+}
+
+// Rules selects reported findings: when Include is set only those rules are kept, Exclude always drops.
+type Rules struct {
+	Include []string
+	Exclude []string
+}
+
+func (r Rules) Allows(rule string) bool {
+	if slices.Contains(r.Exclude, rule) {
+		return false
+	}
+
+	return len(r.Include) == 0 || slices.Contains(r.Include, rule)
+}
+
+func (r Rules) Filter(findings []Finding) []Finding {
+	if len(r.Include) == 0 && len(r.Exclude) == 0 {
+		return findings
+	}
+
+	return slices.DeleteFunc(findings, func(finding Finding) bool {
+		return !r.Allows(finding.Rule)
+	})
+}
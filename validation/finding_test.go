@@ -0,0 +1,37 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDifference(t *testing.T) {
+	a := Finding{Rule: RuleEmpty, ID: "1", Path: "offer.Price", Message: "field offer.Price is empty"}
+	b := Finding{Rule: RuleEmpty, ID: "2", Path: "offer.Price", Message: "field offer.Price is empty"}
+	c := Finding{Rule: RuleArea, ID: "1", Path: "offer.TotalArea", Message: "field offer.TotalArea is too small"}
+
+	moved := a
+	moved.Position = Position{Offset: 10, Line: 2, Column: 3}
+
+	tests := []struct {
+		name   string
+		source []Finding
+		other  []Finding
+		want   []Finding
+	}{
+		{name: "empty", want: []Finding{}},
+		{name: "new", source: []Finding{a, b}, want: []Finding{a, b}},
+		{name: "same", source: []Finding{a, b}, other: []Finding{b, a}, want: []Finding{}},
+		{name: "moved", source: []Finding{moved}, other: []Finding{a}, want: []Finding{}},
+		{name: "duplicates", source: []Finding{a, a, c}, other: []Finding{a}, want: []Finding{a, c}},
+		{name: "other ID", source: []Finding{a}, other: []Finding{b}, want: []Finding{a}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Difference(tt.source, tt.other); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
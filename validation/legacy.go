@@ -0,0 +1,68 @@
+package validation
+
+import (
+	"fmt"
+)
+
+// Deprecated: use CheckFilled, which reports a Finding.
+func CheckString(path string, fieldName string, value string, results *[]string) (isOk bool) {
+	findings := make([]Finding, 0, 1)
+	isOk = CheckFilled(path, fieldName, value, &findings)
+	*results = append(*results, legacyStrings(findings, false)...)
+
+	return isOk
+}
+
+// Deprecated: use CheckFilledWithPos, which reports a Finding.
+func CheckStringWithPos(idx int, path string, fieldName string, value string, results *[]string) (isOk bool) {
+	findings := make([]Finding, 0, 1)
+	isOk = CheckFilledWithPos(idx, path, fieldName, value, &findings)
+	*results = append(*results, legacyStrings(findings, false)...)
+
+	return isOk
+}
+
+// Deprecated: use CheckFilledWithID, which reports a Finding.
+func CheckStringWithID(ID string, path string, fieldName string, value string, results *[]string) (isOk bool) {
+	findings := make([]Finding, 0, 1)
+	isOk = CheckFilledWithID(ID, path, fieldName, value, &findings)
+	*results = append(*results, legacyStrings(findings, true)...)
+
+	return isOk
+}
+
+// Deprecated: use CheckNumberWithID, which reports a Finding, or CheckNonZeroWithID for nullable values.
+func CheckZeroWithID[V int | float64 | float32](ID string, path string, fieldName string, value V, results *[]string) (isOk bool) {
+	findings := make([]Finding, 0, 1)
+	isOk = CheckNumberWithID(ID, path, fieldName, value, &findings)
+	*results = append(*results, legacyStrings(findings, true)...)
+
+	return isOk
+}
+
+// Deprecated: use CheckOneOfWithID, which reports a Finding.
+func CheckEnumWithID(ID string, path string, fieldName string, value string, allowed []string, results *[]string) (isOk bool) {
+	findings := make([]Finding, 0, 1)
+	isOk = CheckOneOfWithID(ID, path, fieldName, value, allowed, &findings)
+	*results = append(*results, legacyStrings(findings, true)...)
+
+	return isOk
+}
+
+// legacyStrings formats findings like the checks did before findings were introduced.
+func legacyStrings(findings []Finding, withID bool) []string {
+	messages := make([]string, 0, len(findings))
+
+	for _, finding := range findings {
+		switch {
+		case !withID:
+			messages = append(messages, finding.Message)
+		case finding.ID == "":
+			messages = append(messages, fmt.Sprintf("%s. InternalID not found", finding.Message))
+		default:
+			messages = append(messages, fmt.Sprintf("%s. InternalID: %s", finding.Message, finding.ID))
+		}
+	}
+
+	return messages
+}
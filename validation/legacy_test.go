@@ -0,0 +1,65 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLegacyChecks(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(results *[]string) bool
+		want  []string
+	}{
+		{
+			name:  "string",
+			check: func(results *[]string) bool { return CheckString("offer", "Name", "", results) },
+			want:  []string{"field offer.Name is empty"},
+		},
+		{
+			name:  "string with position",
+			check: func(results *[]string) bool { return CheckStringWithPos(2, "offer", "Name", "", results) },
+			want:  []string{"field offer[2].Name is empty"},
+		},
+		{
+			name:  "string with ID",
+			check: func(results *[]string) bool { return CheckStringWithID("15", "offer", "Name", "", results) },
+			want:  []string{"field offer.Name is empty. InternalID: 15"},
+		},
+		{
+			name:  "string without ID",
+			check: func(results *[]string) bool { return CheckStringWithID("", "offer", "Name", "", results) },
+			want:  []string{"field offer.Name is empty. InternalID not found"},
+		},
+		{
+			name:  "zero",
+			check: func(results *[]string) bool { return CheckZeroWithID("15", "offer", "Price", 0.0, results) },
+			want:  []string{"field offer.Price is empty. InternalID: 15"},
+		},
+		{
+			name: "enum",
+			check: func(results *[]string) bool {
+				return CheckEnumWithID("15", "offer", "Type", "swap", []string{"sale", "rent"}, results)
+			},
+			want: []string{"field offer.Type has unknown value 'swap'. InternalID: 15"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := make([]string, 0)
+			if tt.check(&results) {
+				t.Fatal("check passed")
+			}
+
+			if !reflect.DeepEqual(results, tt.want) {
+				t.Fatalf("got %q, want %q", results, tt.want)
+			}
+		})
+	}
+
+	results := make([]string, 0)
+	if !CheckStringWithID("15", "offer", "Name", "name", &results) || len(results) != 0 {
+		t.Fatalf("unexpected results %q", results)
+	}
+}
@@ -0,0 +1,308 @@
+package validation
+
+import (
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type State uint8
+
+const (
+	// StateAbsent means the element was not present in the feed.
+	StateAbsent State = iota
+	// StateEmpty means the element was present without a value or with a placeholder like "undefined".
+	StateEmpty
+	// StateInvalid means the element value could not be parsed, the source text is kept in Raw.
+	StateInvalid
+	// StateSet means the element value was parsed successfully.
+	StateSet
+)
+
+func (s State) String() string {
+	switch s {
+	case StateEmpty:
+		return "empty"
+	case StateInvalid:
+		return "invalid"
+	case StateSet:
+		return "set"
+	default:
+		return "absent"
+	}
+}
+
+type Scalar interface {
+	int64 | float64 | bool | time.Time
+}
+
+// Nullable is an XML value that keeps track of whether it was absent, empty, invalid or set.
+// Numbers accept the thousand and decimal separators handled by NormalizeNumber, booleans accept "да"/"нет".
+type Nullable[T Scalar] struct {
+	Value T
+	State State
+	Raw   string
+}
+
+type (
+	Int   = Nullable[int64]
+	Float = Nullable[float64]
+	Bool  = Nullable[bool]
+	Date  = Nullable[time.Time]
+)
+
+func (n Nullable[T]) IsSet() bool {
+	return n.State == StateSet
+}
+
+func (n Nullable[T]) IsZero() bool {
+	var zero T
+
+	switch value := any(n.Value).(type) {
+	case time.Time:
+		return value.IsZero()
+	default:
+		return any(zero) == value
+	}
+}
+
+func (n Nullable[T]) String() string {
+	switch n.State {
+	case StateSet:
+		return formatScalar(n.Value)
+	case StateInvalid:
+		return n.Raw
+	case StateAbsent, StateEmpty:
+		return ""
+	default:
+		return ""
+	}
+}
+
+func (n *Nullable[T]) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var s string
+	if err := d.DecodeElement(&s, &start); err != nil {
+		return err
+	}
+
+	n.parse(s)
+
+	return nil
+}
+
+func (n *Nullable[T]) UnmarshalXMLAttr(attr xml.Attr) error {
+	n.parse(attr.Value)
+
+	return nil
+}
+
+func (n Nullable[T]) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	if n.State == StateAbsent {
+		return nil
+	}
+
+	return e.EncodeElement(n.String(), start)
+}
+
+func (n Nullable[T]) MarshalXMLAttr(name xml.Name) (xml.Attr, error) {
+	if n.State == StateAbsent {
+		return xml.Attr{}, nil
+	}
+
+	return xml.Attr{Name: name, Value: n.String()}, nil
+}
+
+func (n *Nullable[T]) parse(s string) {
+	var zero T
+
+	n.Raw = s
+	n.Value = zero
+
+	if IsUndefined(s) {
+		n.State = StateEmpty
+
+		return
+	}
+
+	value, ok := parseScalar[T](strings.TrimSpace(s))
+	if !ok {
+		n.State = StateInvalid
+
+		return
+	}
+
+	n.Value = value
+	n.State = StateSet
+}
+
+func parseScalar[T Scalar](s string) (T, bool) {
+	var result T
+
+	switch value := any(&result).(type) {
+	case *int64:
+		number := NormalizeNumber(s)
+
+		parsed, err := strconv.ParseInt(number, 10, 64)
+		if err != nil {
+			float, floatErr := strconv.ParseFloat(number, 64)
+			if floatErr != nil || float != float64(int64(float)) {
+				return result, false
+			}
+
+			parsed = int64(float)
+		}
+
+		*value = parsed
+	case *float64:
+		parsed, err := strconv.ParseFloat(NormalizeNumber(s), 64)
+		if err != nil {
+			return result, false
+		}
+
+		*value = parsed
+	case *bool:
+		parsed, ok := ParseBool(s)
+		if !ok {
+			return result, false
+		}
+
+		*value = parsed
+	case *time.Time:
+		parsed, ok := ParseDate(s)
+		if !ok {
+			return result, false
+		}
+
+		*value = parsed
+	}
+
+	return result, true
+}
+
+func formatScalar[T Scalar](value T) string {
+	switch v := any(value).(type) {
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// IsUndefined reports whether the value is empty or a placeholder exported instead of an empty value.
+func IsUndefined(value string) bool {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "undefined", "null", "none", "nan":
+		return true
+	default:
+		return false
+	}
+}
+
+// NormalizeNumber removes thousand separators and converts the decimal separator to a dot,
+// e.g. "1 234,5", "1.234,5" and "1,234.5" all become "1234.5".
+//
+// Spaces and apostrophes are always thousand separators. When both "." and "," are used the last one
+// is the decimal separator, a separator repeated several times is a thousand separator and a single
+// one is the decimal separator, so "1,234" is 1.234 as in Russian feeds.
+func NormalizeNumber(value string) string {
+	if IsUndefined(value) {
+		return ""
+	}
+
+	value = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\u00a0', '\u2009', '\u202f', '\t', '\n', '\r', '\'', '\u2019':
+			return -1
+		default:
+			return r
+		}
+	}, value)
+
+	dot, comma := strings.LastIndexByte(value, '.'), strings.LastIndexByte(value, ',')
+
+	switch {
+	case dot >= 0 && comma >= 0:
+		if dot > comma {
+			return strings.ReplaceAll(value, ",", "")
+		}
+
+		return strings.ReplaceAll(strings.ReplaceAll(value, ".", ""), ",", ".")
+	case strings.Count(value, ",") > 1:
+		return strings.ReplaceAll(value, ",", "")
+	case strings.Count(value, ".") > 1:
+		return strings.ReplaceAll(value, ".", "")
+	default:
+		return strings.ReplaceAll(value, ",", ".")
+	}
+}
+
+func ParseBool(value string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "да", "yes", "+", "true", "1":
+		return true, true
+	case "нет", "no", "-", "false", "0":
+		return false, true
+	default:
+		return false, false
+	}
+}
+
+func ParseDate(value string) (time.Time, bool) {
+	for _, layout := range []string{
+		time.RFC3339Nano,
+		time.DateOnly,
+		"2006-01-02T15:04:05",
+		time.DateTime,
+		"02.01.2006",
+		"02.01.2006 15:04:05",
+		time.RFC1123,
+		time.RFC1123Z,
+	} {
+		date, err := time.Parse(layout, strings.TrimSpace(value))
+		if err == nil {
+			return date, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func CheckSetWithID[T Scalar](ID string, path string, fieldName string, value Nullable[T], results *[]Finding) (isOk bool) {
+	fieldPath := path + "." + fieldName
+
+	switch value.State {
+	case StateAbsent:
+		Add(results, RuleMissing, ID, fieldPath, fmt.Sprintf("field %s is missing", fieldPath))
+	case StateEmpty:
+		Add(results, RuleEmpty, ID, fieldPath, fmt.Sprintf("field %s is empty", fieldPath))
+	case StateInvalid:
+		Add(results, RuleFormat, ID, fieldPath, fmt.Sprintf("field %s has invalid value '%s'", fieldPath, value.Raw))
+	case StateSet:
+		return true
+	}
+
+	return false
+}
+
+func CheckNonZeroWithID[T Scalar](ID string, path string, fieldName string, value Nullable[T], results *[]Finding) (isOk bool) {
+	if !CheckSetWithID(ID, path, fieldName, value, results) {
+		return false
+	}
+
+	if value.IsZero() {
+		fieldPath := path + "." + fieldName
+		Add(results, RuleZero, ID, fieldPath, fmt.Sprintf("field %s is zero", fieldPath))
+
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,145 @@
+package validation
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestNullableStates(t *testing.T) {
+	type lot struct {
+		Price Float `xml:"price"`
+	}
+
+	tests := []struct {
+		name  string
+		xml   string
+		state State
+		value float64
+		rule  string
+	}{
+		{name: "absent", xml: `<lot></lot>`, state: StateAbsent, rule: RuleMissing},
+		{name: "empty", xml: `<lot><price></price></lot>`, state: StateEmpty, rule: RuleEmpty},
+		{name: "placeholder", xml: `<lot><price>undefined</price></lot>`, state: StateEmpty, rule: RuleEmpty},
+		{name: "invalid", xml: `<lot><price>12 rub</price></lot>`, state: StateInvalid, rule: RuleFormat},
+		{name: "zero", xml: `<lot><price>0</price></lot>`, state: StateSet, rule: RuleZero},
+		{name: "set", xml: `<lot><price>1 250 000,50</price></lot>`, state: StateSet, value: 1250000.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoded lot
+			if err := xml.Unmarshal([]byte(tt.xml), &decoded); err != nil {
+				t.Fatal(err)
+			}
+
+			if decoded.Price.State != tt.state || decoded.Price.Value != tt.value {
+				t.Fatalf("got %v %v, want %v %v", decoded.Price.State, decoded.Price.Value, tt.state, tt.value)
+			}
+
+			var results []Finding
+
+			isOk := CheckNonZeroWithID("1", "lot", "Price", decoded.Price, &results)
+
+			switch {
+			case tt.rule == "" && (!isOk || len(results) != 0):
+				t.Fatalf("unexpected findings %v", results)
+			case tt.rule != "" && (isOk || len(results) != 1 || results[0].Rule != tt.rule):
+				t.Fatalf("got findings %v, want rule %s", results, tt.rule)
+			}
+		})
+	}
+}
+
+func TestNullableMarshal(t *testing.T) {
+	type lot struct {
+		XMLName xml.Name `xml:"lot"`
+		Rooms   Int      `xml:"rooms"`
+		Studio  Bool     `xml:"studio"`
+	}
+
+	var decoded lot
+	if err := xml.Unmarshal([]byte(`<lot><rooms>2</rooms></lot>`), &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := xml.Marshal(decoded)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := `<lot><rooms>2</rooms></lot>`; string(data) != want {
+		t.Fatalf("got %s, want %s", data, want)
+	}
+}
+
+func TestNormalizeNumber(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "1234", want: "1234"},
+		{value: "1234.5", want: "1234.5"},
+		{value: "1234,5", want: "1234.5"},
+		{value: "1 234,5", want: "1234.5"},
+		{value: "1 234 567", want: "1234567"},
+		{value: "1.234,5", want: "1234.5"},
+		{value: "1.234.567,89", want: "1234567.89"},
+		{value: "1,234.5", want: "1234.5"},
+		{value: "1,234,567.89", want: "1234567.89"},
+		{value: "1,234,567", want: "1234567"},
+		{value: "1.234.567", want: "1234567"},
+		{value: "1'234.5", want: "1234.5"},
+		{value: "1,234", want: "1.234"},
+		{value: "-12,5", want: "-12.5"},
+		{value: " 42 ", want: "42"},
+		{value: "undefined", want: ""},
+		{value: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeNumber(tt.value); got != tt.want {
+			t.Errorf("NormalizeNumber(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseScalar(t *testing.T) {
+	ints := []struct {
+		value string
+		want  int64
+		ok    bool
+	}{
+		{value: "12", want: 12, ok: true},
+		{value: "1 200", want: 1200, ok: true},
+		{value: "1.200.000", want: 1200000, ok: true},
+		{value: "12.0", want: 12, ok: true},
+		{value: "12.5"},
+		{value: "twelve"},
+	}
+
+	for _, tt := range ints {
+		got, ok := parseScalar[int64](tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseScalar[int64](%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	bools := []struct {
+		value string
+		want  bool
+		ok    bool
+	}{
+		{value: "да", want: true, ok: true},
+		{value: "Нет", ok: true},
+		{value: "true", want: true, ok: true},
+		{value: "0", ok: true},
+		{value: "maybe"},
+	}
+
+	for _, tt := range bools {
+		got, ok := parseScalar[bool](tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseScalar[bool](%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
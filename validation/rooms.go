@@ -0,0 +1,229 @@
+package validation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	studioMaxArea = 45
+	roomMaxArea   = 50
+	baseMaxArea   = 40
+)
+
+type RoomKind uint8
+
+const (
+	// RoomsUnknown means the room count is absent or could not be parsed.
+	RoomsUnknown RoomKind = iota
+	RoomsStudio
+	RoomsFreeLayout
+	// RoomsCount means a flat with Rooms.Count rooms.
+	RoomsCount
+)
+
+// Rooms is the room count of a lot parsed from any platform representation.
+type Rooms struct {
+	Kind  RoomKind
+	Count int64
+	// AtLeast means Count or more rooms, e.g. "10 и более".
+	AtLeast bool
+	// Raw is the source value, kept when it could not be parsed.
+	Raw string
+}
+
+// ParseRooms parses text room counts like "2", "10 и более", "Студия" or "Своб. планировка".
+func ParseRooms(value string) Rooms {
+	value = strings.TrimSpace(value)
+	lower := strings.ToLower(value)
+
+	switch {
+	case IsUndefined(value):
+		return Rooms{}
+	case lower == "студия" || lower == "studio":
+		return Rooms{Kind: RoomsStudio, Raw: value}
+	case strings.HasPrefix(lower, "своб") || strings.Contains(lower, "free") || strings.Contains(lower, "open"):
+		return Rooms{Kind: RoomsFreeLayout, Raw: value}
+	}
+
+	digits := strings.IndexFunc(value, func(r rune) bool { return !unicode.IsDigit(r) })
+	if digits == -1 {
+		digits = len(value)
+	}
+
+	count, err := strconv.ParseInt(value[:digits], 10, 64)
+	if err != nil || count <= 0 {
+		return Rooms{Raw: value}
+	}
+
+	switch strings.TrimSpace(lower[digits:]) {
+	case "":
+		return Rooms{Kind: RoomsCount, Count: count, Raw: value}
+	case "+", "и более":
+		return Rooms{Kind: RoomsCount, Count: count, AtLeast: true, Raw: value}
+	default:
+		return Rooms{Raw: value}
+	}
+}
+
+// RoomsFromCount returns the rooms of a numeric count, zero or less is unknown.
+func RoomsFromCount(count int64) Rooms {
+	if count <= 0 {
+		return Rooms{}
+	}
+
+	return Rooms{Kind: RoomsCount, Count: count, Raw: strconv.FormatInt(count, 10)}
+}
+
+func (r Rooms) String() string {
+	switch r.Kind {
+	case RoomsStudio:
+		return "studio"
+	case RoomsFreeLayout:
+		return "free layout"
+	case RoomsCount:
+		if r.AtLeast {
+			return strconv.FormatInt(r.Count, 10) + "+"
+		}
+
+		return strconv.FormatInt(r.Count, 10)
+	default:
+		return r.Raw
+	}
+}
+
+// HasLiving reports whether the flat is expected to have a living area separate from the kitchen.
+func (r Rooms) HasLiving() bool {
+	return r.Kind != RoomsStudio && r.Kind != RoomsFreeLayout
+}
+
+// Layout holds the room related values of a lot checked by CheckRooms.
+type Layout struct {
+	// Field is the name of the room count field in messages.
+	Field string
+	Rooms Rooms
+	// Studio and OpenPlan are separate flags of platforms that have them.
+	Studio   bool
+	OpenPlan bool
+	// RoomType is the separate/adjacent rooms value, it needs at least two rooms.
+	RoomType string
+	// FlatType is the cian layout type: rooms, openPlan or studio.
+	FlatType string
+	// RoomAreas are the areas of single rooms listed in the RoomAreasField.
+	RoomAreas      []float64
+	RoomAreasField string
+	Total          float64
+}
+
+// Effective applies the studio and open plan flags to the room count.
+func (l Layout) Effective() Rooms {
+	switch {
+	case l.Studio:
+		return Rooms{Kind: RoomsStudio, Raw: l.Rooms.Raw}
+	case l.OpenPlan && l.Rooms.Kind == RoomsUnknown:
+		return Rooms{Kind: RoomsFreeLayout, Raw: l.Rooms.Raw}
+	default:
+		return l.Rooms
+	}
+}
+
+// HasLiving reports whether the flat is expected to have a living area, which studios and open plans lack.
+func (l Layout) HasLiving() bool {
+	return !l.OpenPlan && l.Effective().HasLiving()
+}
+
+// CheckRooms checks that the room count, layout flags, room areas and total area agree.
+func CheckRooms(ID string, path string, layout Layout, results *[]Finding) (isOk bool) {
+	start := len(*results)
+	field := path + "." + layout.Field
+	rooms := layout.Effective()
+
+	if layout.Rooms.Kind == RoomsUnknown && layout.Rooms.Raw != "" {
+		Add(results, RuleFormat, ID, field, fmt.Sprintf("field %s has invalid value '%s'", field, layout.Rooms.Raw))
+	}
+
+	if layout.Studio && layout.Rooms.Kind == RoomsCount && layout.Rooms.Count > 1 {
+		Add(results, RuleRooms, ID, field, fmt.Sprintf("field %s is %v for a studio", field, layout.Rooms.Count))
+	}
+
+	if layout.RoomType != "" && (rooms.Kind == RoomsStudio || rooms.Kind == RoomsCount && rooms.Count < 2) {
+		Add(results, RuleRooms, ID, path+".RoomType",
+			fmt.Sprintf("field %s.RoomType '%s' needs at least 2 rooms, got %s", path, layout.RoomType, rooms))
+	}
+
+	if expected, ok := flatTypeKind(layout.FlatType); ok && rooms.Kind != RoomsUnknown && expected != rooms.Kind {
+		Add(results, RuleRooms, ID, path+".FlatType",
+			fmt.Sprintf("field %s.FlatType '%s' does not match %s %s", path, layout.FlatType, field, rooms))
+	}
+
+	maxAreas := rooms.Count
+	if rooms.Kind == RoomsStudio {
+		maxAreas = 1
+	}
+
+	if (rooms.Kind == RoomsStudio || rooms.Kind == RoomsCount) && int64(len(layout.RoomAreas)) > maxAreas {
+		Add(results, RuleRooms, ID, path+"."+layout.RoomAreasField,
+			fmt.Sprintf("field %s.%s contains %v values for %s rooms", path, layout.RoomAreasField, len(layout.RoomAreas), rooms))
+	}
+
+	checkAreaRange(ID, path, rooms, layout.Total, results)
+
+	return len(*results) == start
+}
+
+func flatTypeKind(flatType string) (RoomKind, bool) {
+	switch flatType {
+	case "studio":
+		return RoomsStudio, true
+	case "openPlan":
+		return RoomsFreeLayout, true
+	case "rooms":
+		return RoomsCount, true
+	default:
+		return RoomsUnknown, false
+	}
+}
+
+// MaxTotalArea returns the largest typical total area of a flat with the given number of rooms.
+// Zero rooms means a studio.
+func MaxTotalArea(rooms int64) float64 {
+	if rooms <= 0 {
+		return studioMaxArea
+	}
+
+	return float64(baseMaxArea + roomMaxArea*rooms)
+}
+
+func checkAreaRange(ID string, path string, rooms Rooms, total float64, results *[]Finding) {
+	var count int64
+
+	switch rooms.Kind {
+	case RoomsStudio:
+	case RoomsCount:
+		count = rooms.Count
+	case RoomsUnknown, RoomsFreeLayout:
+		return
+	}
+
+	if !CheckMinArea(ID, path, count, total, results) || rooms.AtLeast {
+		return
+	}
+
+	if maxArea := MaxTotalArea(count); total > maxArea {
+		Add(results, RuleArea, ID, path+".TotalArea",
+			fmt.Sprintf("field %s.TotalArea (%v) is too big for %s rooms, expected at most %v", path, total, rooms, maxArea))
+	}
+}
+
+// CheckLivingArea reports an empty living area unless the layout is a studio or free layout.
+func CheckLivingArea(ID string, path string, fieldName string, layout Layout, living float64, results *[]Finding) (isOk bool) {
+	if living != 0 || !layout.HasLiving() {
+		return true
+	}
+
+	Add(results, RuleEmpty, ID, path+"."+fieldName, fmt.Sprintf("field %s is empty", fieldName))
+
+	return false
+}
@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"testing"
+)
+
+func TestParseRooms(t *testing.T) {
+	tests := []struct {
+		value string
+		want  Rooms
+	}{
+		{value: "", want: Rooms{}},
+		{value: "undefined", want: Rooms{}},
+		{value: " 2 ", want: Rooms{Kind: RoomsCount, Count: 2, Raw: "2"}},
+		{value: "10 и более", want: Rooms{Kind: RoomsCount, Count: 10, AtLeast: true, Raw: "10 и более"}},
+		{value: "4+", want: Rooms{Kind: RoomsCount, Count: 4, AtLeast: true, Raw: "4+"}},
+		{value: "Студия", want: Rooms{Kind: RoomsStudio, Raw: "Студия"}},
+		{value: "studio", want: Rooms{Kind: RoomsStudio, Raw: "studio"}},
+		{value: "Своб. планировка", want: Rooms{Kind: RoomsFreeLayout, Raw: "Своб. планировка"}},
+		{value: "open plan", want: Rooms{Kind: RoomsFreeLayout, Raw: "open plan"}},
+		{value: "0", want: Rooms{Raw: "0"}},
+		{value: "two", want: Rooms{Raw: "two"}},
+		{value: "2 комнаты", want: Rooms{Raw: "2 комнаты"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := ParseRooms(tt.value); got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckRooms(t *testing.T) {
+	tests := []struct {
+		name   string
+		layout Layout
+		paths  []string
+	}{
+		{name: "valid", layout: Layout{Rooms: RoomsFromCount(2), RoomAreas: []float64{15, 12}, Total: 54}},
+		{name: "unknown", layout: Layout{Rooms: ParseRooms("two"), Total: 54}, paths: []string{"offer.Rooms"}},
+		{name: "studio with rooms", layout: Layout{Rooms: RoomsFromCount(3), Studio: true, Total: 25}, paths: []string{"offer.Rooms"}},
+		{name: "room type of studio", layout: Layout{Rooms: ParseRooms("студия"), RoomType: "раздельные", Total: 25}, paths: []string{"offer.RoomType"}},
+		{name: "room type of one room", layout: Layout{Rooms: RoomsFromCount(1), RoomType: "смежные", Total: 35}, paths: []string{"offer.RoomType"}},
+		{name: "flat type", layout: Layout{Rooms: RoomsFromCount(2), FlatType: "studio", Total: 54}, paths: []string{"offer.FlatType"}},
+		{name: "too many room areas", layout: Layout{Rooms: RoomsFromCount(1), RoomAreas: []float64{10, 10}, Total: 40}, paths: []string{"offer.RoomSpace"}},
+		{name: "too small", layout: Layout{Rooms: RoomsFromCount(3), Total: 30}, paths: []string{"offer.TotalArea"}},
+		{name: "too big", layout: Layout{Rooms: RoomsFromCount(1), Total: 120}, paths: []string{"offer.TotalArea"}},
+		{name: "at least", layout: Layout{Rooms: ParseRooms("5+"), Total: 400}},
+		{name: "studio too big", layout: Layout{Rooms: ParseRooms("студия"), Total: 60}, paths: []string{"offer.TotalArea"}},
+		{name: "free layout", layout: Layout{Rooms: ParseRooms("свободная"), Total: 300}},
+		{name: "open plan", layout: Layout{OpenPlan: true, Total: 300}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := tt.layout
+			layout.Field = "Rooms"
+			layout.RoomAreasField = "RoomSpace"
+
+			results := make([]Finding, 0)
+			if ok := CheckRooms("1", "offer", layout, &results); ok != (len(tt.paths) == 0) {
+				t.Fatalf("got %v for findings %v", ok, results)
+			}
+
+			if len(results) != len(tt.paths) {
+				t.Fatalf("got findings %v, want paths %v", results, tt.paths)
+			}
+
+			for idx, finding := range results {
+				if finding.Path != tt.paths[idx] || finding.ID != "1" {
+					t.Fatalf("got findings %v, want paths %v", results, tt.paths)
+				}
+			}
+		})
+	}
+}
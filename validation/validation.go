@@ -3,6 +3,7 @@ package validation
 import (
 	"encoding/xml"
 	"fmt"
+	"math"
 	"strconv"
 )
 
@@ -10,6 +11,7 @@ const (
 	MsgEmptyFeed string = "feed is empty"
 )
 
+// Deprecated: use Int, which also tells absent, empty and invalid values apart.
 type CustomInt64 struct {
 	Int64 int64
 	Valid bool
@@ -33,13 +35,14 @@ func (ci *CustomInt64) UnmarshalXML(d *xml.Decoder, start xml.StartElement) erro
 	}
 
 	ci.Int64 = int64(customI)
+	ci.Valid = true
 
 	return nil
 }
 
-func CheckString(path string, fieldName string, value string, results *[]string) (isOk bool) {
+func CheckFilled(path string, fieldName string, value string, results *[]Finding) (isOk bool) {
 	if value == "" {
-		*results = append(*results, fmt.Sprintf("field %s.%s is empty", path, fieldName))
+		Add(results, RuleEmpty, "", path+"."+fieldName, fmt.Sprintf("field %s.%s is empty", path, fieldName))
 
 		return false
 	}
@@ -47,9 +50,10 @@ func CheckString(path string, fieldName string, value string, results *[]string)
 	return true
 }
 
-func CheckStringWithPos(idx int, path string, fieldName string, value string, results *[]string) (isOk bool) {
+func CheckFilledWithPos(idx int, path string, fieldName string, value string, results *[]Finding) (isOk bool) {
 	if value == "" {
-		*results = append(*results, fmt.Sprintf("field %s[%d].%s is empty", path, idx, fieldName))
+		fieldPath := fmt.Sprintf("%s[%d].%s", path, idx, fieldName)
+		Add(results, RuleEmpty, "", fieldPath, fmt.Sprintf("field %s is empty", fieldPath))
 
 		return false
 	}
@@ -57,16 +61,9 @@ func CheckStringWithPos(idx int, path string, fieldName string, value string, re
 	return true
 }
 
-func CheckStringWithID(ID string, path string, fieldName string, value string, results *[]string) (isOk bool) {
-	var idMessage string
-	if ID == "" {
-		idMessage = "InternalID not found"
-	} else {
-		idMessage = fmt.Sprintf("InternalID: %s", ID)
-	}
-
+func CheckFilledWithID(ID string, path string, fieldName string, value string, results *[]Finding) (isOk bool) {
 	if value == "" {
-		*results = append(*results, fmt.Sprintf("field %s.%s is empty. %s", path, fieldName, idMessage))
+		Add(results, RuleEmpty, ID, path+"."+fieldName, fmt.Sprintf("field %s.%s is empty", path, fieldName))
 
 		return false
 	}
@@ -74,12 +71,93 @@ func CheckStringWithID(ID string, path string, fieldName string, value string, r
 	return true
 }
 
-func CheckZeroWithID[V int | float64 | float32](ID string, path string, fieldName string, value V, results *[]string) (isOk bool) {
+func CheckNumberWithID[V int | float64 | float32](ID string, path string, fieldName string, value V, results *[]Finding) (isOk bool) {
 	if value == 0 {
-		*results = append(*results, fmt.Sprintf("field %s.%s is empty. InternalID: %s", path, fieldName, ID))
+		Add(results, RuleEmpty, ID, path+"."+fieldName, fmt.Sprintf("field %s.%s is empty", path, fieldName))
 
 		return false
 	}
 
 	return true
 }
+
+const (
+	areaTolerance     = 0.05
+	areaMinDifference = 1.0
+	studioMinArea     = 12
+	roomMinArea       = 10
+	baseMinArea       = 8
+)
+
+func CheckAreaSum(ID string, path string, total float64, living float64, kitchen float64, results *[]Finding) (isOk bool) {
+	if total == 0 || living+kitchen <= total {
+		return true
+	}
+
+	Add(results, RuleArea, ID, path+".TotalArea",
+		fmt.Sprintf("fields %s.LivingArea + %s.KitchenArea (%v) are bigger than TotalArea (%v)", path, path, living+kitchen, total))
+
+	return false
+}
+
+func CheckRoomsAreaSum(ID string, path string, living float64, rooms []float64, results *[]Finding) (isOk bool) {
+	if living == 0 || len(rooms) == 0 {
+		return true
+	}
+
+	var sum float64
+	for _, room := range rooms {
+		sum += room
+	}
+
+	if math.Abs(sum-living) <= math.Max(areaMinDifference, living*areaTolerance) {
+		return true
+	}
+
+	Add(results, RuleArea, ID, path+".RoomsArea",
+		fmt.Sprintf("sum of %s.RoomsArea (%v) does not match LivingArea (%v)", path, sum, living))
+
+	return false
+}
+
+// MinTotalArea returns the smallest plausible total area of a flat with the given number of rooms.
+// Zero rooms means a studio.
+func MinTotalArea(rooms int64) float64 {
+	if rooms <= 0 {
+		return studioMinArea
+	}
+
+	return float64(baseMinArea + roomMinArea*rooms)
+}
+
+func CheckMinArea(ID string, path string, rooms int64, total float64, results *[]Finding) (isOk bool) {
+	if total == 0 {
+		return true
+	}
+
+	minArea := MinTotalArea(rooms)
+	if total >= minArea {
+		return true
+	}
+
+	Add(results, RuleArea, ID, path+".TotalArea",
+		fmt.Sprintf("field %s.TotalArea (%v) is too small for %v rooms, expected at least %v", path, total, rooms, minArea))
+
+	return false
+}
+
+func CheckOneOfWithID(ID string, path string, fieldName string, value string, allowed []string, results *[]Finding) (isOk bool) {
+	if value == "" {
+		return true
+	}
+
+	for _, item := range allowed {
+		if value == item {
+			return true
+		}
+	}
+
+	Add(results, RuleEnum, ID, path+"."+fieldName, fmt.Sprintf("field %s.%s has unknown value '%s'", path, fieldName, value))
+
+	return false
+}
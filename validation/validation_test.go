@@ -0,0 +1,89 @@
+package validation
+
+import (
+	"testing"
+)
+
+func TestCheckAreaSum(t *testing.T) {
+	tests := []struct {
+		name                   string
+		total, living, kitchen float64
+		ok                     bool
+	}{
+		{name: "fits", total: 50, living: 30, kitchen: 10, ok: true},
+		{name: "equal", total: 40, living: 30, kitchen: 10, ok: true},
+		{name: "no total", living: 30, kitchen: 10, ok: true},
+		{name: "too big", total: 35, living: 30, kitchen: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := make([]Finding, 0)
+			if ok := CheckAreaSum("1", "offer", tt.total, tt.living, tt.kitchen, &results); ok != tt.ok || len(results) != boolCount(!tt.ok) {
+				t.Fatalf("got %v, %v", ok, results)
+			}
+
+			if !tt.ok && (results[0].Rule != RuleArea || results[0].Path != "offer.TotalArea" || results[0].ID != "1") {
+				t.Fatalf("unexpected finding %+v", results[0])
+			}
+		})
+	}
+}
+
+func TestCheckRoomsAreaSum(t *testing.T) {
+	tests := []struct {
+		name   string
+		living float64
+		rooms  []float64
+		ok     bool
+	}{
+		{name: "exact", living: 30, rooms: []float64{18, 12}, ok: true},
+		{name: "within a meter", living: 10, rooms: []float64{10.9}, ok: true},
+		{name: "within tolerance", living: 100, rooms: []float64{60, 44}, ok: true},
+		{name: "no living", rooms: []float64{18, 12}, ok: true},
+		{name: "no rooms", living: 30, ok: true},
+		{name: "too small", living: 30, rooms: []float64{18}},
+		{name: "too big", living: 100, rooms: []float64{60, 46}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := make([]Finding, 0)
+			if ok := CheckRoomsAreaSum("1", "offer", tt.living, tt.rooms, &results); ok != tt.ok || len(results) != boolCount(!tt.ok) {
+				t.Fatalf("got %v, %v", ok, results)
+			}
+		})
+	}
+}
+
+func TestCheckMinArea(t *testing.T) {
+	tests := []struct {
+		name  string
+		rooms int64
+		total float64
+		ok    bool
+	}{
+		{name: "studio", rooms: 0, total: 12, ok: true},
+		{name: "small studio", rooms: 0, total: 11},
+		{name: "two rooms", rooms: 2, total: 28, ok: true},
+		{name: "small two rooms", rooms: 2, total: 27.9},
+		{name: "no total", rooms: 3, ok: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := make([]Finding, 0)
+			if ok := CheckMinArea("1", "offer", tt.rooms, tt.total, &results); ok != tt.ok || len(results) != boolCount(!tt.ok) {
+				t.Fatalf("got %v, %v", ok, results)
+			}
+		})
+	}
+}
+
+func boolCount(value bool) int {
+	if value {
+		return 1
+	}
+
+	return 0
+}